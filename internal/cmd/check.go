@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"io/fs"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/spf13/cobra"
+)
+
+// checkOptions are shared by commands that find errors in the CODEOWNERS file.
+type checkOptions struct {
+	offline       bool
+	deadRules     bool
+	shadowedRules bool
+}
+
+func (opts *checkOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&opts.offline, "offline", false, "Check for errors locally without querying GitHub.")
+	cmd.Flags().BoolVar(&opts.deadRules, "dead-rules", false, "Also report rules that match no files in the repository.")
+	cmd.Flags().BoolVar(&opts.shadowedRules, "shadowed-rules", false, "Also report rules fully overridden by later rules.")
+}
+
+// ensure makes sure a repository and authentication are available if GitHub will be queried.
+func (opts *checkOptions) ensure(globalOpts *GlobalOptions) (err error) {
+	// Remote repositories are always read from GitHub.
+	if opts.offline && !globalOpts.Remote {
+		return
+	}
+
+	err = globalOpts.EnsureRepository()
+	if err != nil {
+		return
+	}
+
+	return globalOpts.IsAuthenticated()
+}
+
+func findErrors(globalOpts *GlobalOptions, opts *checkOptions) (codeowners.Errors, error) {
+	errors, err := queryErrors(globalOpts, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.deadRules && !opts.shadowedRules {
+		return errors, nil
+	}
+
+	c, err := opts.codeowners(globalOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := opts.files(globalOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.deadRules {
+		errors = append(errors, c.Unmatched(files)...)
+	}
+
+	if opts.shadowedRules {
+		errors = append(errors, c.Shadowed(files)...)
+	}
+
+	errors.Sort()
+
+	return errors, nil
+}
+
+// rootFS returns the tree that errors were queried for.
+func (opts *checkOptions) rootFS(globalOpts *GlobalOptions) (fs.FS, error) {
+	ref, err := opts.queriedRef(globalOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref == "" {
+		return globalOpts.RootFS()
+	}
+
+	return globalOpts.treeFS(ref)
+}
+
+// codeowners opens the CODEOWNERS file that errors were queried for.
+func (opts *checkOptions) codeowners(globalOpts *GlobalOptions) (*codeowners.Codeowners, error) {
+	fs, err := opts.rootFS(globalOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return globalOpts.codeownersFS(fs)
+}
+
+// files returns the files in the tree that errors were queried for.
+func (opts *checkOptions) files(globalOpts *GlobalOptions) ([]string, error) {
+	ref, err := opts.queriedRef(globalOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref == "" {
+		return globalOpts.Files()
+	}
+
+	return globalOpts.treeFiles(ref)
+}
+
+// queriedRef returns the commit GitHub was queried for if it may differ from RootFS, which reads the working tree;
+// otherwise, it returns an empty string.
+func (opts *checkOptions) queriedRef(globalOpts *GlobalOptions) (string, error) {
+	if opts.offline || globalOpts.Remote || globalOpts.Ref != "" || globalOpts.fs != nil {
+		return "", nil
+	}
+
+	return globalOpts.RefName()
+}
+
+func queryErrors(globalOpts *GlobalOptions, opts *checkOptions) (codeowners.Errors, error) {
+	if opts.offline {
+		root, err := globalOpts.RootFS()
+		if err != nil {
+			return nil, err
+		}
+
+		path := codeowners.Find(root, globalOpts.codeownersOptions()...)
+		if path == "" {
+			return nil, &UsageError{Err: codeowners.ErrNotFound}
+		}
+
+		f, err := codeowners.ParseFS(root, path, globalOpts.codeownersOptions()...)
+		if err != nil {
+			return nil, err
+		}
+
+		return codeowners.Check(f), nil
+	}
+
+	client, err := globalOpts.GQLClient()
+	if err != nil {
+		return nil, err
+	}
+
+	refName, err := globalOpts.RefName()
+	if err != nil {
+		return nil, err
+	}
+
+	return codeowners.QueryErrors(client, globalOpts.Repo, refName)
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/spf13/cobra"
+)
+
+func CoverageCommand(globalOpts *GlobalOptions) *cobra.Command {
+	opts := &coverageOptions{
+		GlobalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Reports files without code owners",
+		Long: "Shows the percentage of files with code owners, a summary for each directory, and files without owners. " +
+			"Files without owners are matched by no rule or by a rule with no owners.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if opts.min < 0 || opts.min > 100 {
+				return newUsageError("--min must be between 0 and 100")
+			}
+
+			return coverage(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Show coverage as JSON.")
+	opts.addExportFlags(cmd, func() bool { return opts.json })
+	cmd.Flags().Float64Var(&opts.min, "min", 0, "Fail if the percentage of owned files is less than `percent`.")
+
+	return cmd
+}
+
+type coverageOptions struct {
+	*GlobalOptions
+
+	json bool
+	min  float64
+}
+
+func coverage(opts *coverageOptions) (err error) {
+	c, err := opts.Codeowners()
+	if err != nil {
+		return
+	}
+
+	files, err := opts.Files()
+	if err != nil {
+		return
+	}
+
+	report := newCoverageReport(c, files)
+	if opts.json || opts.isExporting() {
+		err = printJson(opts.GlobalOptions, report)
+	} else {
+		err = printCoverage(opts.GlobalOptions, report)
+	}
+	if err != nil {
+		return
+	}
+
+	if report.Coverage < opts.min {
+		return newFoundError("coverage %.1f%% is less than minimum %.1f%%", report.Coverage, opts.min)
+	}
+
+	return
+}
+
+type coverageReport struct {
+	Files       int                 `json:"files"`
+	Owned       int                 `json:"owned"`
+	Coverage    float64             `json:"coverage"`
+	Directories []directoryCoverage `json:"directories"`
+	Unowned     []string            `json:"unowned"`
+}
+
+type directoryCoverage struct {
+	Path     string  `json:"path"`
+	Files    int     `json:"files"`
+	Owned    int     `json:"owned"`
+	Coverage float64 `json:"coverage"`
+}
+
+func newCoverageReport(c *codeowners.Codeowners, files []string) coverageReport {
+	report := coverageReport{
+		Unowned: []string{},
+	}
+
+	// Roll up each file into every directory containing it.
+	dirs := make(map[string]*directoryCoverage)
+	for _, file := range files {
+		owned := len(c.Owners(file)) > 0
+
+		report.Files++
+		if owned {
+			report.Owned++
+		} else {
+			report.Unowned = append(report.Unowned, file)
+		}
+
+		for dir := path.Dir(file); ; dir = path.Dir(dir) {
+			d, ok := dirs[dir]
+			if !ok {
+				d = &directoryCoverage{Path: dir}
+				dirs[dir] = d
+			}
+
+			d.Files++
+			if owned {
+				d.Owned++
+			}
+
+			if dir == "." {
+				break
+			}
+		}
+	}
+
+	report.Coverage = percent(report.Owned, report.Files)
+	report.Directories = make([]directoryCoverage, 0, len(dirs))
+	for _, d := range dirs {
+		d.Coverage = percent(d.Owned, d.Files)
+		report.Directories = append(report.Directories, *d)
+	}
+	sort.Slice(report.Directories, func(i, j int) bool {
+		return report.Directories[i].Path < report.Directories[j].Path
+	})
+	sort.Strings(report.Unowned)
+
+	return report
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	return float64(n) * 100 / float64(total)
+}
+
+func printCoverage(opts *GlobalOptions, report coverageReport) error {
+	w := opts.Console.Stdout()
+	fmt.Fprintf(w, "Coverage: %.1f%% (%d of %d files owned)\n\n", report.Coverage, report.Owned, report.Files)
+
+	tp := newTablePrinter(opts)
+	tp.AddField("DIRECTORY")
+	tp.AddField("FILES")
+	tp.AddField("OWNED")
+	tp.AddField("COVERAGE")
+	tp.EndRow()
+
+	for _, d := range report.Directories {
+		tp.AddField(d.Path)
+		tp.AddField(strconv.Itoa(d.Files))
+		tp.AddField(strconv.Itoa(d.Owned))
+		tp.AddField(fmt.Sprintf("%.1f%%", d.Coverage))
+		tp.EndRow()
+	}
+
+	if err := tp.Render(); err != nil {
+		return err
+	}
+
+	if len(report.Unowned) > 0 {
+		fmt.Fprintln(w, "\nUnowned files:")
+		for _, file := range report.Unowned {
+			fmt.Fprintln(w, indent+file)
+		}
+	}
+
+	return nil
+}
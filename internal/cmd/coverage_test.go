@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/heaths/gh-codeowners/internal/gittest"
+	"github.com/heaths/go-console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	content := heredoc.Doc(`
+		/src/ @developers
+		docs/ @writers
+		docs/generated/
+	`)
+	fs := fstest.MapFS{
+		"CODEOWNERS":                {Data: []byte(content)},
+		"main.go":                   {Data: []byte("package main")},
+		"src/lib.go":                {Data: []byte("package src")},
+		"docs/README.md":            {Data: []byte("# README")},
+		"docs/generated/index.html": {Data: []byte("<html/>")},
+	}
+
+	tests := []struct {
+		name       string
+		tty        bool
+		json       bool
+		min        float64
+		wantStdout string
+		wantErr    string
+	}{
+		{
+			name: "text",
+			tty:  true,
+			wantStdout: heredoc.Doc(`
+				Coverage: 40.0% (2 of 5 files owned)
+
+				DIRECTORY       FILES  OWNED  COVERAGE
+				.               5      2      40.0%
+				docs            2      1      50.0%
+				docs/generated  1      0      0.0%
+				src             1      1      100.0%
+
+				Unowned files:
+				  CODEOWNERS
+				  docs/generated/index.html
+				  main.go
+			`),
+		},
+		{
+			name: "json",
+			json: true,
+			wantStdout: `{"files":5,"owned":2,"coverage":40,"directories":[` +
+				`{"path":".","files":5,"owned":2,"coverage":40},` +
+				`{"path":"docs","files":2,"owned":1,"coverage":50},` +
+				`{"path":"docs/generated","files":1,"owned":0,"coverage":0},` +
+				`{"path":"src","files":1,"owned":1,"coverage":100}],` +
+				`"unowned":["CODEOWNERS","docs/generated/index.html","main.go"]}`,
+		},
+		{
+			name:       "below minimum",
+			json:       true,
+			min:        50,
+			wantStdout: `{"files":5,"owned":2,"coverage":40,`,
+			wantErr:    "coverage 40.0% is less than minimum 50.0%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake(
+				console.WithStdoutTTY(tt.tty),
+				console.WithSize(80, 24),
+			)
+
+			opts := coverageOptions{
+				GlobalOptions: &GlobalOptions{
+					Console: fake,
+
+					colorDisabled: true,
+					fs:            fs,
+				},
+				json: tt.json,
+				min:  tt.min,
+			}
+
+			err := coverage(&opts)
+			stdout, _, _ := fake.Buffers()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Equal(t, ExitFound, ExitCode(err, false))
+				assert.Contains(t, stdout.String(), tt.wantStdout)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
+
+func TestCoverage_untracked(t *testing.T) {
+	root := gittest.NewRepo(t, map[string]string{
+		".gitignore": "*.log\n",
+		"CODEOWNERS": "*.go @developers\n",
+		"lib.go":     "package main",
+		"main.go":    "package main",
+	})
+
+	// Ignored and untracked files are not counted.
+	gittest.WriteFiles(t, root, map[string]string{
+		"debug.log": "ignored",
+		"new.go":    "package main",
+	})
+
+	fake := console.Fake()
+	opts := coverageOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+
+			colorDisabled: true,
+			root:          root,
+		},
+		json: true,
+	}
+
+	err := coverage(&opts)
+	require.NoError(t, err)
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, `{"files":4,"owned":2,"coverage":50,"directories":[`+
+		`{"path":".","files":4,"owned":2,"coverage":50}],`+
+		`"unowned":[".gitignore","CODEOWNERS"]}`, stdout.String())
+}
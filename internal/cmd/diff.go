@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/spf13/cobra"
+)
+
+func DiffCommand(globalOpts *GlobalOptions) *cobra.Command {
+	opts := &diffOptions{
+		GlobalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Compares owners between revisions",
+		Long: "Shows which files changed owners between two branches, tags, or commits, or between two CODEOWNERS files. " +
+			"Files are listed from the tree of <new>, or tracked files if <new> is a CODEOWNERS file, " +
+			"and grouped by how their owners changed.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			opts.old = args[0]
+			opts.new = args[1]
+			return diff(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Show changes grouped by owners as JSON.")
+	opts.addExportFlags(cmd, func() bool { return opts.json })
+	cmd.Flags().BoolVar(&opts.stat, "stat", false, "Show only the number of files for each change in owners.")
+
+	return cmd
+}
+
+type diffOptions struct {
+	*GlobalOptions
+
+	json bool
+	stat bool
+	old  string
+	new  string
+}
+
+type transition struct {
+	OldOwners []string `json:"oldOwners"`
+	NewOwners []string `json:"newOwners"`
+	Count     int      `json:"count"`
+	Files     []string `json:"files,omitempty"`
+}
+
+func diff(opts *diffOptions) (err error) {
+	before, _, err := opts.revision(opts.old)
+	if err != nil {
+		return
+	}
+
+	after, files, err := opts.revision(opts.new)
+	if err != nil {
+		return
+	}
+
+	changes := codeowners.Diff(before, after, files)
+	transitions := groupTransitions(changes)
+	if opts.json || opts.isExporting() {
+		if opts.stat {
+			for i := range transitions {
+				transitions[i].Files = nil
+			}
+		}
+		return printJson(opts.GlobalOptions, transitions)
+	}
+
+	w := opts.Console.Stdout()
+	if opts.stat {
+		width := 0
+		for _, t := range transitions {
+			if n := utf8.RuneCountInString(t.String()); n > width {
+				width = n
+			}
+		}
+
+		for _, t := range transitions {
+			fmt.Fprintf(w, "%-*s  %s\n", width, t.String(), plural(t.Count, "file"))
+		}
+
+		fmt.Fprintf(w, "%s changed owners\n", plural(len(changes), "file"))
+		return
+	}
+
+	for i, t := range transitions {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "%s (%s)\n", t, plural(t.Count, "file"))
+		for _, file := range t.Files {
+			fmt.Fprintln(w, indent+file)
+		}
+	}
+
+	return
+}
+
+// revision opens a CODEOWNERS file if rev is a file, or the CODEOWNERS file in the tree at ref.
+// It also returns the files to compare, which are the tracked files for a CODEOWNERS file or the files in the tree.
+func (opts *diffOptions) revision(rev string) (*codeowners.Codeowners, []string, error) {
+	if stat, err := os.Stat(rev); err == nil && !stat.IsDir() {
+		path, err := filepath.Abs(rev)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c, err := codeowners.Open(os.DirFS(filepath.Dir(path)), filepath.Base(path), opts.codeownersOptions()...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		files, err := opts.Files()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return c, files, nil
+	}
+
+	fs, err := opts.treeFS(rev)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := openCodeownersOrEmpty(opts.GlobalOptions, fs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files, err := opts.treeFiles(rev)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c, files, nil
+}
+
+// groupTransitions groups changes by their old and new owners, sorted by the number of files descending.
+func groupTransitions(changes []codeowners.Change) []transition {
+	var transitions []*transition
+	index := make(map[string]*transition)
+	for _, change := range changes {
+		key := strings.ToLower(strings.Join(change.OldOwners, " ") + "\x00" + strings.Join(change.NewOwners, " "))
+		t, ok := index[key]
+		if !ok {
+			t = &transition{
+				OldOwners: change.OldOwners,
+				NewOwners: change.NewOwners,
+			}
+			index[key] = t
+			transitions = append(transitions, t)
+		}
+
+		t.Files = append(t.Files, change.Path)
+		t.Count++
+	}
+
+	sort.SliceStable(transitions, func(i, j int) bool {
+		if transitions[i].Count != transitions[j].Count {
+			return transitions[i].Count > transitions[j].Count
+		}
+		return strings.ToLower(transitions[i].String()) < strings.ToLower(transitions[j].String())
+	})
+
+	results := make([]transition, len(transitions))
+	for i, t := range transitions {
+		results[i] = *t
+	}
+
+	return results
+}
+
+func (t transition) String() string {
+	owners := func(owners []string) string {
+		if len(owners) == 0 {
+			return "(no owners)"
+		}
+		return strings.Join(owners, " ")
+	}
+
+	return owners(t.OldOwners) + " → " + owners(t.NewOwners)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/heaths/gh-codeowners/internal/gittest"
+	"github.com/heaths/go-console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	root := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": "* @heaths\ndocs/ @writers\n",
+		"main.go":    "package main\n",
+		"docs/a.md":  "# A\n",
+		"docs/b.md":  "# B\n",
+		"src/x.go":   "package src\n",
+	})
+
+	gittest.Run(t, root, "tag", "v1")
+
+	newContent := "* @heaths\ndocs/ @org/docs\nsrc/ @org/core\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte(newContent), 0644))
+	gittest.Commit(t, root, "Reorg")
+
+	oldFile := filepath.Join(t.TempDir(), "CODEOWNERS")
+	require.NoError(t, os.WriteFile(oldFile, []byte("* @heaths\n"), 0644))
+
+	tests := []struct {
+		name       string
+		old        string
+		new        string
+		json       bool
+		stat       bool
+		wantStdout string
+	}{
+		{
+			name: "refs",
+			old:  "v1",
+			new:  "HEAD",
+			wantStdout: heredoc.Doc(`
+				@writers → @org/docs (2 files)
+				  docs/a.md
+				  docs/b.md
+
+				@heaths → @org/core (1 file)
+				  src/x.go
+			`),
+		},
+		{
+			name: "stat",
+			old:  "v1",
+			new:  "HEAD",
+			stat: true,
+			wantStdout: heredoc.Doc(`
+				@writers → @org/docs  2 files
+				@heaths → @org/core   1 file
+				3 files changed owners
+			`),
+		},
+		{
+			name: "json",
+			old:  "HEAD",
+			new:  "v1",
+			json: true,
+			wantStdout: `[{"oldOwners":["@org/docs"],"newOwners":["@writers"],"count":2,"files":["docs/a.md","docs/b.md"]},` +
+				`{"oldOwners":["@org/core"],"newOwners":["@heaths"],"count":1,"files":["src/x.go"]}]`,
+		},
+		{
+			name: "stat (json)",
+			old:  "v1",
+			new:  "HEAD",
+			json: true,
+			stat: true,
+			wantStdout: `[{"oldOwners":["@writers"],"newOwners":["@org/docs"],"count":2},` +
+				`{"oldOwners":["@heaths"],"newOwners":["@org/core"],"count":1}]`,
+		},
+		{
+			name: "files",
+			old:  oldFile,
+			new:  filepath.Join(root, "CODEOWNERS"),
+			stat: true,
+			wantStdout: heredoc.Doc(`
+				@heaths → @org/docs  2 files
+				@heaths → @org/core  1 file
+				3 files changed owners
+			`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake()
+			opts := diffOptions{
+				GlobalOptions: &GlobalOptions{
+					Console: fake,
+
+					colorDisabled: true,
+					root:          root,
+				},
+				json: tt.json,
+				stat: tt.stat,
+				old:  tt.old,
+				new:  tt.new,
+			}
+
+			err := diff(&opts)
+			require.NoError(t, err)
+
+			stdout, _, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by the CLI.
+const (
+	ExitOK     = 0
+	ExitFound  = 1
+	ExitUsage  = 2
+	ExitClient = 3
+)
+
+// FoundError is returned when a check finds problems e.g., CODEOWNERS errors or coverage below a minimum.
+type FoundError struct {
+	Message string
+}
+
+func (e *FoundError) Error() string {
+	return e.Message
+}
+
+func newFoundError(format string, args ...any) error {
+	return &FoundError{
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// UsageError is returned when a command, arguments, flags, or configuration are invalid
+// e.g., no repository or CODEOWNERS file was found.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+func newUsageError(format string, args ...any) error {
+	return &UsageError{
+		Err: fmt.Errorf(format, args...),
+	}
+}
+
+// ClientError is returned when authentication fails or GitHub cannot be queried.
+type ClientError struct {
+	Err error
+}
+
+func (e *ClientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ClientError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for an error returned from a command.
+// Problems found by checks return ExitOK if exitZero is true.
+// Errors not found by checks or caused by the client return ExitUsage.
+func ExitCode(err error, exitZero bool) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var foundErr *FoundError
+	if errors.As(err, &foundErr) {
+		if exitZero {
+			return ExitOK
+		}
+		return ExitFound
+	}
+
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		return ExitUsage
+	}
+
+	var (
+		clientErr *ClientError
+		httpErr   api.HTTPError
+		gqlErr    api.GQLError
+		urlErr    *url.Error
+	)
+	if errors.As(err, &clientErr) ||
+		errors.As(err, &httpErr) ||
+		errors.As(err, &gqlErr) ||
+		errors.As(err, &urlErr) {
+		return ExitClient
+	}
+
+	return ExitUsage
+}
+
+// SetUsageErrors returns a UsageError for unknown commands, and for invalid arguments and flags to root or any subcommand.
+// Call it after adding all subcommands.
+func SetUsageErrors(root *cobra.Command) {
+	root.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &UsageError{Err: err}
+	})
+
+	// Cobra returns an error for unknown commands only when the root command has no Args of its own.
+	if root.Args == nil && !root.Runnable() {
+		root.Args = cobra.ArbitraryArgs
+		root.RunE = func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			if cmd.SuggestionsMinimumDistance <= 0 {
+				cmd.SuggestionsMinimumDistance = 2
+			}
+
+			// Match the error and suggestions cobra would return.
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "unknown command %q for %q", args[0], cmd.CommandPath())
+			if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 {
+				sb.WriteString("\n\nDid you mean this?\n")
+				for _, s := range suggestions {
+					fmt.Fprintf(&sb, "\t%s\n", s)
+				}
+			}
+			return &UsageError{Err: errors.New(sb.String())}
+		}
+	}
+
+	var visit func(*cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		if args := cmd.Args; args != nil {
+			cmd.Args = func(cmd *cobra.Command, a []string) error {
+				if err := args(cmd, a); err != nil {
+					return &UsageError{Err: err}
+				}
+				return nil
+			}
+		}
+
+		// Cobra validates required and mutually exclusive flags after PreRunE, so validate them first.
+		if cmd.Runnable() && cmd.PreRun == nil {
+			preRunE := cmd.PreRunE
+			cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+				if err := cmd.ValidateRequiredFlags(); err != nil {
+					return &UsageError{Err: err}
+				}
+				if err := cmd.ValidateFlagGroups(); err != nil {
+					return &UsageError{Err: err}
+				}
+				if preRunE != nil {
+					return preRunE(cmd, args)
+				}
+				return nil
+			}
+		}
+
+		for _, c := range cmd.Commands() {
+			visit(c)
+		}
+	}
+	visit(root)
+}
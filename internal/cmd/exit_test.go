@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		exitZero bool
+		want     int
+	}{
+		{
+			name: "no error",
+			want: ExitOK,
+		},
+		{
+			name: "found",
+			err:  fmt.Errorf("lint: %w", newFoundError("found %d errors", 2)),
+			want: ExitFound,
+		},
+		{
+			name:     "found (exit zero)",
+			err:      newFoundError("found %d errors", 2),
+			exitZero: true,
+			want:     ExitOK,
+		},
+		{
+			name:     "usage",
+			err:      &UsageError{Err: errors.New(`unknown flag: --nope`)},
+			exitZero: true,
+			want:     ExitUsage,
+		},
+		{
+			name: "usage (wrapped)",
+			err:  fmt.Errorf("pr: %w", newUsageError("parse issue or pull request number: %w", strconv.ErrSyntax)),
+			want: ExitUsage,
+		},
+		{
+			name:     "not found",
+			err:      &UsageError{Err: codeowners.ErrNotFound},
+			exitZero: true,
+			want:     ExitUsage,
+		},
+		{
+			name: "other",
+			err:  errors.New("failed to run git"),
+			want: ExitUsage,
+		},
+		{
+			name: "auth",
+			err:  &ClientError{Err: errors.New("authentication token not found for host github.com")},
+			want: ExitClient,
+		},
+		{
+			name: "http",
+			err:  fmt.Errorf("failed to list files: %w", api.HTTPError{StatusCode: 404}),
+			want: ExitClient,
+		},
+		{
+			name: "graphql",
+			err:  api.GQLError{Errors: []api.GQLErrorItem{{Message: "not found"}}},
+			want: ExitClient,
+		},
+		{
+			name: "network",
+			err:  &url.Error{Op: "Post", URL: "https://api.github.com/graphql", Err: errors.New("connection refused")},
+			want: ExitClient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err, tt.exitZero))
+		})
+	}
+}
+
+func TestSetUsageErrors(t *testing.T) {
+	newRoot := func() *cobra.Command {
+		root := &cobra.Command{
+			Use:           "codeowners",
+			SilenceErrors: true,
+			SilenceUsage:  true,
+		}
+
+		lint := &cobra.Command{
+			Use:  "lint",
+			Args: cobra.NoArgs,
+			RunE: func(_ *cobra.Command, _ []string) error {
+				return &UsageError{Err: codeowners.ErrNotFound}
+			},
+		}
+		lint.Flags().Bool("fix", false, "")
+		lint.Flags().Bool("json", false, "")
+		lint.MarkFlagsMutuallyExclusive("fix", "json")
+		root.AddCommand(lint)
+
+		SetUsageErrors(root)
+		return root
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+		want    int
+	}{
+		{
+			name: "help",
+			want: ExitOK,
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"lnt"},
+			wantErr: "unknown command \"lnt\" for \"codeowners\"\n\nDid you mean this?\n\tlint\n",
+			want:    ExitUsage,
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"lint", "--nope"},
+			wantErr: "unknown flag: --nope",
+			want:    ExitUsage,
+		},
+		{
+			name:    "arguments",
+			args:    []string{"lint", "extra"},
+			wantErr: `unknown command "extra" for "codeowners lint"`,
+			want:    ExitUsage,
+		},
+		{
+			name:    "exclusive flags",
+			args:    []string{"lint", "--fix", "--json"},
+			wantErr: "if any flags in the group [fix json] are set none of the others can be; [fix json] were all set",
+			want:    ExitUsage,
+		},
+		{
+			name:    "not found",
+			args:    []string{"lint"},
+			wantErr: "CODEOWNERS not found",
+			want:    ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newRoot()
+			root.SetArgs(tt.args)
+			root.SetOut(io.Discard)
+
+			err := root.Execute()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, ExitCode(err, false))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/spf13/cobra"
+)
+
+func FilesCommand(globalOpts *GlobalOptions) *cobra.Command {
+	opts := &filesOptions{
+		GlobalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "files <owner>...",
+		Short: "Lists files owned by owners",
+		Long: "Lists every tracked file owned by any of the given owners e.g., @user, @org/team, or an email address. " +
+			"The leading @ is optional for users and teams.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			opts.owners = args
+			return files(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.directories, "directories", false, "Collapse files into the fewest directories owned entirely by the owners.")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Show files as JSON.")
+	opts.addExportFlags(cmd, func() bool { return opts.json })
+
+	return cmd
+}
+
+type filesOptions struct {
+	*GlobalOptions
+
+	directories bool
+	json        bool
+	owners      []string
+}
+
+func files(opts *filesOptions) (err error) {
+	c, err := opts.Codeowners()
+	if err != nil {
+		return
+	}
+
+	all, err := opts.Files()
+	if err != nil {
+		return
+	}
+
+	owners := make([]string, len(opts.owners))
+	for i, owner := range opts.owners {
+		if !strings.Contains(owner, "@") {
+			owner = "@" + owner
+		}
+		owners[i] = owner
+	}
+
+	owned := ownedFiles(c, all, owners)
+	if opts.directories {
+		owned = collapseDirectories(all, owned)
+	}
+
+	if opts.json || opts.isExporting() {
+		return printJson(opts.GlobalOptions, owned)
+	}
+
+	for _, file := range owned {
+		fmt.Fprintln(opts.Console.Stdout(), file)
+	}
+
+	return
+}
+
+func ownedFiles(c *codeowners.Codeowners, files, owners []string) []string {
+	owned := []string{}
+	for _, file := range files {
+		for _, owner := range c.Owners(file) {
+			if stringSliceContains(owner, owners) {
+				owned = append(owned, file)
+				break
+			}
+		}
+	}
+
+	return owned
+}
+
+// collapseDirectories replaces owned files with the topmost directory containing only owned files.
+// Directories end with a slash, and the repository root is "/".
+func collapseDirectories(all, owned []string) []string {
+	total := make(map[string]int)
+	for _, file := range all {
+		for _, dir := range parentDirs(file) {
+			total[dir]++
+		}
+	}
+
+	count := make(map[string]int)
+	for _, file := range owned {
+		for _, dir := range parentDirs(file) {
+			count[dir]++
+		}
+	}
+
+	seen := make(map[string]bool)
+	collapsed := []string{}
+	for _, file := range owned {
+		entry := file
+
+		// Parent directories are ordered from the root down so the first fully owned directory is the topmost.
+		for _, dir := range parentDirs(file) {
+			if count[dir] == total[dir] {
+				if dir == "." {
+					entry = "/"
+				} else {
+					entry = dir + "/"
+				}
+				break
+			}
+		}
+
+		if !seen[entry] {
+			seen[entry] = true
+			collapsed = append(collapsed, entry)
+		}
+	}
+
+	return collapsed
+}
+
+// parentDirs returns the directories containing file starting with the repository root ".".
+func parentDirs(file string) []string {
+	var dirs []string
+	for dir := path.Dir(file); ; dir = path.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+		if dir == "." {
+			break
+		}
+	}
+
+	return dirs
+}
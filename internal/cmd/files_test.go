@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/heaths/go-console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestFiles(t *testing.T) {
+	content := heredoc.Doc(`
+		* @heaths
+		docs/ @org/writers
+		docs/api/ @org/api
+		*.md @org/writers
+	`)
+	fs := fstest.MapFS{
+		"CODEOWNERS":         {Data: []byte(content)},
+		"README.md":          {Data: []byte("# README")},
+		"main.go":            {Data: []byte("package main")},
+		"docs/index.md":      {Data: []byte("# Docs")},
+		"docs/guide/a.md":    {Data: []byte("# A")},
+		"docs/guide/b.png":   {Data: []byte{}},
+		"docs/api/index.md":  {Data: []byte("# API")},
+		"docs/api/spec.yaml": {Data: []byte{}},
+	}
+
+	tests := []struct {
+		name        string
+		owners      []string
+		directories bool
+		json        bool
+		wantStdout  string
+	}{
+		{
+			name:   "files",
+			owners: []string{"@org/writers"},
+			wantStdout: heredoc.Doc(`
+				README.md
+				docs/api/index.md
+				docs/guide/a.md
+				docs/guide/b.png
+				docs/index.md
+			`),
+		},
+		{
+			name:        "directories",
+			owners:      []string{"org/writers"},
+			directories: true,
+			wantStdout: heredoc.Doc(`
+				README.md
+				docs/api/index.md
+				docs/guide/
+				docs/index.md
+			`),
+		},
+		{
+			name:        "multiple owners",
+			owners:      []string{"@ORG/WRITERS", "@org/api"},
+			directories: true,
+			json:        true,
+			wantStdout:  `["README.md","docs/"]`,
+		},
+		{
+			name:        "all owners",
+			owners:      []string{"@heaths", "@org/writers", "@org/api"},
+			directories: true,
+			wantStdout:  "/\n",
+		},
+		{
+			name:       "no files",
+			owners:     []string{"@nobody"},
+			json:       true,
+			wantStdout: `[]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake()
+			opts := filesOptions{
+				GlobalOptions: &GlobalOptions{
+					Console: fake,
+
+					colorDisabled: true,
+					fs:            fs,
+				},
+				directories: tt.directories,
+				json:        tt.json,
+				owners:      tt.owners,
+			}
+
+			err := files(&opts)
+			require.NoError(t, err)
+
+			stdout, _, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
+
+func TestFiles_remote(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`DefaultBranch`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"defaultBranchRef":{"name":"main"}}}}`)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"expression":"main:.github/CODEOWNERS"`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"object":{"__typename":"Blob","byteSize":32,"text":"* @heaths\ndocs/ @org/writers\n"}}}}`)
+
+	// Files are listed with a single request instead of a query for each directory.
+	gock.New("https://api.github.com").
+		Get("/repos/heaths/gh-codeowners/git/trees/main").
+		MatchParam("recursive", "1").
+		Reply(200).
+		JSON(`{
+			"tree": [
+				{"path": ".github", "type": "tree"},
+				{"path": ".github/CODEOWNERS", "type": "blob"},
+				{"path": "docs", "type": "tree"},
+				{"path": "docs/guide", "type": "tree"},
+				{"path": "docs/guide/a.md", "type": "blob"},
+				{"path": "docs/index.md", "type": "blob"},
+				{"path": "main.go", "type": "blob"}
+			],
+			"truncated": false
+		}`)
+
+	fake := console.Fake()
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := filesOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+			Remote:  true,
+			Repo:    repo,
+
+			host:          "github.com",
+			authToken:     "***",
+			colorDisabled: true,
+		},
+		owners: []string{"@org/writers"},
+	}
+
+	err = files(&opts)
+	require.NoError(t, err)
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, "docs/guide/a.md\ndocs/index.md\n", stdout.String())
+	assert.True(t, gock.IsDone())
+}
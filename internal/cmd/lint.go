@@ -2,17 +2,22 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
-	"github.com/cli/go-gh"
-	"github.com/cli/go-gh/pkg/api"
 	"github.com/heaths/gh-codeowners/internal/codeowners"
-	"github.com/heaths/gh-codeowners/internal/git"
+	"github.com/heaths/gh-codeowners/internal/report"
 	"github.com/spf13/cobra"
 )
 
 const (
 	indent = "  "
+
+	formatText       = "text"
+	formatJSON       = "json"
+	formatSARIF      = "sarif"
+	formatCheckstyle = "checkstyle"
+	formatJUnit      = "junit"
 )
 
 func LintCommand(globalOpts *GlobalOptions) *cobra.Command {
@@ -25,12 +30,14 @@ func LintCommand(globalOpts *GlobalOptions) *cobra.Command {
 		Short: "Checks CODEOWNERS for errors",
 		Long:  "Checks your CODEOWNERS files for errors as determined by GitHub.",
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			err = opts.EnsureRepository()
-			if err != nil {
-				return
+			if !cmd.Flags().Changed("github-actions") {
+				opts.githubActions = os.Getenv("GITHUB_ACTIONS") == "true"
+			}
+			if opts.githubActions {
+				opts.stepSummary = os.Getenv("GITHUB_STEP_SUMMARY")
 			}
 
-			err = opts.IsAuthenticated()
+			err = opts.ensure(opts.GlobalOptions)
 			if err != nil {
 				return
 			}
@@ -39,46 +46,85 @@ func LintCommand(globalOpts *GlobalOptions) *cobra.Command {
 		},
 	}
 
+	opts.addFlags(cmd)
+	opts.addRefFlag(cmd)
+
 	cmd.Flags().BoolVar(&opts.fix, "fix", false, "Fix errors in the CODEOWNERS file.")
-	cmd.Flags().BoolVar(&opts.json, "json", false, "Show errors as JSON.")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Show errors as JSON. Same as --format json.")
+	opts.addExportFlags(cmd, func() bool {
+		return opts.json || opts.format == formatJSON
+	})
+	StringEnumVarP(cmd, &opts.format, "format", "", formatText, []string{formatText, formatJSON, formatSARIF, formatCheckstyle, formatJUnit}, "Output format")
+	cmd.Flags().BoolVar(&opts.githubActions, "github-actions", false, "Write GitHub Actions annotations and a job summary. Enabled by default in GitHub Actions.")
 	cmd.Flags().BoolVar(&opts.unknownOwners, "unknown-owners", false, "Only list unknown owners.")
 	cmd.MarkFlagsMutuallyExclusive("fix", "json", "unknown-owners")
+	cmd.MarkFlagsMutuallyExclusive("fix", "format", "unknown-owners")
+	cmd.MarkFlagsMutuallyExclusive("json", "format")
+	cmd.MarkFlagsMutuallyExclusive("fix", "ref")
 
 	return cmd
 }
 
 type lintOptions struct {
 	*GlobalOptions
+	checkOptions
 
 	fix           bool
+	format        string
+	githubActions bool
 	json          bool
+	stepSummary   string
 	unknownOwners bool
 }
 
 func lint(opts *lintOptions) (err error) {
-	clientOpts := &api.ClientOptions{
-		Host:      opts.host,
-		AuthToken: opts.authToken,
-	}
-	client, err := gh.GQLClient(clientOpts)
+	errors, err := findErrors(opts.GlobalOptions, &opts.checkOptions)
 	if err != nil {
 		return
 	}
 
-	refName, err := git.RefName()
-	if err != nil {
-		return
+	if opts.fix {
+		return fix(opts, errors)
 	}
 
-	errors, err := codeowners.QueryErrors(client, opts.Repo, refName)
+	err = printLint(opts, errors)
 	if err != nil {
 		return
 	}
 
-	if opts.json {
+	if len(errors) > 0 {
+		return newFoundError("found %s in CODEOWNERS", plural(len(errors), "error"))
+	}
+
+	return
+}
+
+func printLint(opts *lintOptions, errors codeowners.Errors) (err error) {
+	if opts.githubActions {
+		err = githubActions(opts, errors)
+		if err != nil || opts.isText() {
+			return
+		}
+	}
+
+	if opts.json || opts.format == formatJSON || opts.isExporting() {
 		return printJson(opts.GlobalOptions, errors)
 	}
 
+	switch opts.format {
+	case formatSARIF:
+		return report.WriteSARIF(opts.Console.Stdout(), errors)
+	case formatCheckstyle:
+		return report.WriteCheckstyle(opts.Console.Stdout(), errors)
+	case formatJUnit:
+		// List a testcase for each rule in the same CODEOWNERS file errors were found in.
+		c, err := opts.checkOptions.codeowners(opts.GlobalOptions)
+		if err != nil {
+			return err
+		}
+		return report.WriteJUnit(opts.Console.Stdout(), c.File, errors)
+	}
+
 	if opts.unknownOwners {
 		missing := errors.UnknownOwners()
 		for _, owner := range missing {
@@ -88,20 +134,97 @@ func lint(opts *lintOptions) (err error) {
 		return
 	}
 
+	printErrors(opts.GlobalOptions, errors)
+	return
+}
+
+// githubActions writes annotations in place of text, or to stderr when another format is written to stdout,
+// and appends a summary to the job summary file if set.
+func githubActions(opts *lintOptions, errors codeowners.Errors) (err error) {
+	w := opts.Console.Stdout()
+	if !opts.isText() {
+		w = opts.Console.Stderr()
+	}
+
+	err = report.WriteAnnotations(w, errors)
+	if err != nil || opts.stepSummary == "" {
+		return
+	}
+
+	f, err := os.OpenFile(opts.stepSummary, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %w", err)
+	}
+	defer f.Close()
+
+	return report.WriteSummary(f, errors)
+}
+
+// isText returns whether errors are written as text instead of another format or a list of unknown owners.
+func (opts *lintOptions) isText() bool {
+	return !opts.json && !opts.unknownOwners && !opts.isExporting() && (opts.format == "" || opts.format == formatText)
+}
+
+func fix(opts *lintOptions, errors codeowners.Errors) (err error) {
+	root, err := opts.RootFS()
+	if err != nil {
+		return
+	}
+
+	content, missing, err := codeowners.Fix(root, errors)
+	if err == codeowners.ErrNotFound {
+		return &UsageError{Err: err}
+	} else if err != nil {
+		return
+	}
+
+	if content == nil {
+		fmt.Fprintln(opts.Console.Stdout(), "No unknown owners to remove")
+		return
+	}
+
+	path := errors.Path()
+	if path == "" {
+		path = codeowners.Find(root)
+	}
+
+	err = opts.WriteFile(path, content)
+	if err != nil {
+		return
+	}
+
+	removed := 0
+	for _, e := range errors {
+		if e.UnknownOwner() != "" {
+			removed++
+		}
+	}
+
+	fmt.Fprintf(opts.Console.Stdout(), "Removed %d unknown owner(s) from %s\n", removed, path)
+	if len(missing) > 0 {
+		fmt.Fprintln(opts.Console.Stdout())
+		printErrors(opts.GlobalOptions, missing)
+
+		return newFoundError("%s left without owners in %s", plural(len(missing), "rule"), path)
+	}
+
+	return
+}
+
+func printErrors(opts *GlobalOptions, errors codeowners.Errors) {
 	if opts.IsColorEnabled() {
 		cs := opts.Console.ColorScheme()
 		remove := cs.ColorFunc(opts.Color.Error)
 
 		prettyPrint := func(e codeowners.Error) {
 			for _, line := range strings.Split(e.Message, "\n") {
-				if e.Kind == codeowners.ErrorKindUnknownOwner {
+				if token := e.Token(); token != "" {
 					line = strings.TrimSpace(line)
 					if line == "^" {
 						fmt.Fprintln(opts.Console.Stdout())
 						return
 					} else if line == strings.TrimSpace(e.Source) {
-						owner := e.UnknownOwner()
-						line = indent + strings.ReplaceAll(line, owner, remove(owner))
+						line = indent + strings.ReplaceAll(line, token, remove(token))
 					}
 				}
 
@@ -119,6 +242,4 @@ func lint(opts *lintOptions) (err error) {
 	for _, e := range errors {
 		fmt.Fprintln(opts.Console.Stdout(), e.Message)
 	}
-
-	return
 }
@@ -0,0 +1,656 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/heaths/gh-codeowners/internal/git"
+	"github.com/heaths/gh-codeowners/internal/gittest"
+	"github.com/heaths/go-console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestLintFix(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	content := heredoc.Doc(`
+		# comment
+		* @heaths
+		docs/ @writers # Documentation
+	`)
+
+	root := t.TempDir()
+	path := filepath.Join(root, "CODEOWNERS")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	gittest.Init(t, root)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"codeowners": {
+						"errors": [
+							{
+								"path": "CODEOWNERS",
+								"kind": "Unknown owner",
+								"line": 3,
+								"column": 7,
+								"source": "docs/ @writers # Documentation"
+							}
+						]
+					}
+				}
+			}
+		}`)
+
+	fake := console.Fake()
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := lintOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+			Repo:    repo,
+
+			host:          "github.com",
+			authToken:     "***",
+			colorDisabled: true,
+			root:          root,
+		},
+		fix: true,
+	}
+
+	err = lint(&opts)
+	var foundErr *FoundError
+	require.ErrorAs(t, err, &foundErr)
+	assert.Equal(t, "1 rule left without owners in CODEOWNERS", err.Error())
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, heredoc.Doc(`
+		# comment
+		* @heaths
+		docs/ # Documentation
+	`), string(got))
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, heredoc.Doc(`
+		Removed 1 unknown owner(s) from CODEOWNERS
+
+		Missing owners on line 3: all owners were removed so matching files have no owners
+
+		  docs/ # Documentation
+		  ^
+	`), stdout.String())
+}
+
+func TestLint_offline(t *testing.T) {
+	content := heredoc.Doc(`
+		* @heaths
+		*.md @writers
+		docs/ @writers
+		old/ @heaths
+	`)
+	fs := fstest.MapFS{
+		"CODEOWNERS":     {Data: []byte(content)},
+		"main.go":        {Data: []byte("package main")},
+		"docs/README.md": {Data: []byte("# README")},
+	}
+
+	tests := []struct {
+		name       string
+		opts       checkOptions
+		json       bool
+		format     string
+		wantStdout string
+		wantErr    bool
+	}{
+		{
+			name: "no errors",
+			opts: checkOptions{offline: true},
+		},
+		{
+			name: "dead and shadowed rules",
+			opts: checkOptions{
+				offline:       true,
+				deadRules:     true,
+				shadowedRules: true,
+			},
+			wantErr: true,
+			wantStdout: heredoc.Doc(`
+				Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3
+
+				  *.md @writers
+				  ^
+				No matching files on line 4: old/ does not match any files in the repository
+
+				  old/ @heaths
+				  ^
+			`),
+		},
+		{
+			name: "shadowed rules (json)",
+			opts: checkOptions{
+				offline:       true,
+				shadowedRules: true,
+			},
+			json:    true,
+			wantErr: true,
+			wantStdout: `[{"kind":"Shadowed rule","path":"CODEOWNERS","line":2,"column":1,"source":"*.md @writers",` +
+				`"message":"Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3\n\n  *.md @writers\n  ^"}]`,
+		},
+		{
+			name: "shadowed rules (checkstyle)",
+			opts: checkOptions{
+				offline:       true,
+				shadowedRules: true,
+			},
+			format:  formatCheckstyle,
+			wantErr: true,
+			wantStdout: heredoc.Doc(`
+				<?xml version="1.0" encoding="UTF-8"?>
+				<checkstyle version="4.3">
+				  <file name="CODEOWNERS">
+				    <error line="2" column="1" severity="warning" message="Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3" source="gh-codeowners.shadowed-rule"></error>
+				  </file>
+				</checkstyle>
+			`),
+		},
+		{
+			name: "no errors (junit)",
+			opts: checkOptions{
+				offline: true,
+			},
+			format: formatJUnit,
+			wantStdout: heredoc.Doc(`
+				<?xml version="1.0" encoding="UTF-8"?>
+				<testsuites name="gh-codeowners" tests="4" failures="0">
+				  <testsuite name="CODEOWNERS" tests="4" failures="0">
+				    <testcase name="line 1: * @heaths" classname="CODEOWNERS" file="CODEOWNERS" line="1"></testcase>
+				    <testcase name="line 2: *.md @writers" classname="CODEOWNERS" file="CODEOWNERS" line="2"></testcase>
+				    <testcase name="line 3: docs/ @writers" classname="CODEOWNERS" file="CODEOWNERS" line="3"></testcase>
+				    <testcase name="line 4: old/ @heaths" classname="CODEOWNERS" file="CODEOWNERS" line="4"></testcase>
+				  </testsuite>
+				</testsuites>
+			`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake()
+			opts := lintOptions{
+				GlobalOptions: &GlobalOptions{
+					Console: fake,
+
+					colorDisabled: true,
+					fs:            fs,
+				},
+				checkOptions: tt.opts,
+				format:       tt.format,
+				json:         tt.json,
+			}
+
+			err := lint(&opts)
+			if tt.wantErr {
+				var foundErr *FoundError
+				require.ErrorAs(t, err, &foundErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			stdout, _, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
+
+func TestLint_deadRulesUntracked(t *testing.T) {
+	root := gittest.NewRepo(t, map[string]string{
+		".gitignore": "*.log\n",
+		"CODEOWNERS": "* @heaths\n*.log @heaths\n/new.go @heaths\n",
+		"main.go":    "package main",
+	})
+
+	// Rules matching only ignored or untracked files are dead.
+	gittest.WriteFiles(t, root, map[string]string{
+		"debug.log": "ignored",
+		"new.go":    "package main",
+	})
+
+	fake := console.Fake()
+	opts := lintOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+
+			colorDisabled: true,
+			root:          root,
+		},
+		checkOptions: checkOptions{
+			offline:   true,
+			deadRules: true,
+		},
+	}
+
+	err := lint(&opts)
+	var foundErr *FoundError
+	require.ErrorAs(t, err, &foundErr)
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, heredoc.Doc(`
+		No matching files on line 2: *.log does not match any files in the repository
+
+		  *.log @heaths
+		  ^
+		No matching files on line 3: /new.go does not match any files in the repository
+
+		  /new.go @heaths
+		  ^
+	`), stdout.String())
+}
+
+func TestLint_remote(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`DefaultBranch`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"defaultBranchRef":{"name":"main"}}}}`)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"ref":"main"`).
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"codeowners": {
+						"errors": [
+							{
+								"path": ".github/CODEOWNERS",
+								"kind": "Unknown owner",
+								"line": 1,
+								"column": 3,
+								"source": "* @nobody",
+								"message": "Unknown owner on line 1: make sure @nobody exists and has write access to the repository\n\n  * @nobody\n    ^"
+							}
+						]
+					}
+				}
+			}
+		}`)
+
+	fake := console.Fake()
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := lintOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+			Remote:  true,
+			Repo:    repo,
+
+			host:          "github.com",
+			authToken:     "***",
+			colorDisabled: true,
+		},
+		unknownOwners: true,
+	}
+
+	err = lint(&opts)
+	var foundErr *FoundError
+	require.ErrorAs(t, err, &foundErr)
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, "@nobody\n", stdout.String())
+	assert.True(t, gock.IsDone())
+}
+
+func TestLint_unpushed(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	origin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(origin, "CODEOWNERS"), []byte("* @heaths\n"), 0644))
+	gittest.Init(t, origin)
+
+	root := filepath.Join(t.TempDir(), "clone")
+	gittest.Run(t, "", "clone", "--quiet", origin, root)
+
+	base, err := git.ResolveRef(root, "HEAD")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @nobody\n"), 0644))
+	gittest.Commit(t, root, "Change owners")
+
+	head, err := git.ResolveRef(root, "HEAD")
+	require.NoError(t, err)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"ref":"` + base + `"`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"codeowners":{"errors":[]}}}}`)
+
+	fake := console.Fake()
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := lintOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+			Repo:    repo,
+
+			host:          "github.com",
+			authToken:     "***",
+			colorDisabled: true,
+			root:          root,
+		},
+	}
+
+	err = lint(&opts)
+	require.NoError(t, err)
+
+	_, stderr, _ := fake.Buffers()
+	assert.Equal(t, heredoc.Docf(`
+		warning: HEAD %[1]s has not been pushed; checking for errors at %[2]s instead
+		warning: CODEOWNERS differs between %[2]s and HEAD so errors may not match; pass --offline to check for errors locally
+	`, head[:7], base[:7]), stderr.String())
+	assert.True(t, gock.IsDone())
+}
+
+func TestLint_deadRulesAtRef(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	root := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": "* @heaths\n",
+		"main.go":    "package main",
+	})
+
+	head, err := git.ResolveRef(root, "HEAD")
+	require.NoError(t, err)
+
+	// Only committed rules are checked by GitHub, so uncommitted rules should not be reported.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @heaths\n/missing/ @heaths\n"), 0644))
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"ref":"` + head + `"`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"codeowners":{"errors":[]}}}}`)
+
+	fake := console.Fake()
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := lintOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+			Repo:    repo,
+
+			host:          "github.com",
+			authToken:     "***",
+			colorDisabled: true,
+			root:          root,
+		},
+		checkOptions: checkOptions{
+			deadRules: true,
+		},
+	}
+
+	err = lint(&opts)
+	require.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestLint_junitAtRef(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	root := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": "* @heaths\n",
+	})
+
+	head, err := git.ResolveRef(root, "HEAD")
+	require.NoError(t, err)
+
+	// Rules GitHub did not check should not be listed as passing.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @heaths\ndocs/ @writers\n"), 0644))
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"ref":"` + head + `"`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"codeowners":{"errors":[]}}}}`)
+
+	fake := console.Fake()
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := lintOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+			Repo:    repo,
+
+			host:          "github.com",
+			authToken:     "***",
+			colorDisabled: true,
+			root:          root,
+		},
+		format: formatJUnit,
+	}
+
+	err = lint(&opts)
+	require.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, heredoc.Doc(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<testsuites name="gh-codeowners" tests="1" failures="0">
+		  <testsuite name="CODEOWNERS" tests="1" failures="0">
+		    <testcase name="line 1: * @heaths" classname="CODEOWNERS" file="CODEOWNERS" line="1"></testcase>
+		  </testsuite>
+		</testsuites>
+	`), stdout.String())
+}
+
+func TestLint_githubActions(t *testing.T) {
+	fs := fstest.MapFS{
+		"CODEOWNERS":     {Data: []byte("* @heaths\n*.md @writers\ndocs/ @writers\n")},
+		"main.go":        {Data: []byte("package main")},
+		"docs/README.md": {Data: []byte("# README")},
+	}
+
+	tests := []struct {
+		name       string
+		json       bool
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "text",
+			wantStdout: "::warning file=CODEOWNERS,line=2,col=1,title=Shadowed rule::Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3%0A%0A  *.md @writers%0A  ^\n",
+		},
+		{
+			name: "json",
+			json: true,
+			wantStdout: `[{"kind":"Shadowed rule","path":"CODEOWNERS","line":2,"column":1,"source":"*.md @writers",` +
+				`"message":"Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3\n\n  *.md @writers\n  ^"}]`,
+			wantStderr: "::warning file=CODEOWNERS,line=2,col=1,title=Shadowed rule::Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3%0A%0A  *.md @writers%0A  ^\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := filepath.Join(t.TempDir(), "summary.md")
+			require.NoError(t, os.WriteFile(summary, []byte("# Build\n\n"), 0644))
+
+			fake := console.Fake()
+			opts := lintOptions{
+				GlobalOptions: &GlobalOptions{
+					Console: fake,
+
+					colorDisabled: true,
+					fs:            fs,
+				},
+				checkOptions: checkOptions{
+					offline:       true,
+					shadowedRules: true,
+				},
+				githubActions: true,
+				json:          tt.json,
+				stepSummary:   summary,
+			}
+
+			err := lint(&opts)
+			var foundErr *FoundError
+			require.ErrorAs(t, err, &foundErr)
+			assert.Equal(t, "found 1 error in CODEOWNERS", err.Error())
+
+			stdout, stderr, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+			assert.Equal(t, tt.wantStderr, stderr.String())
+
+			got, err := os.ReadFile(summary)
+			require.NoError(t, err)
+			assert.Equal(t, heredoc.Doc(`
+				# Build
+
+				## CODEOWNERS
+
+				Found 1 error(s).
+
+				| File | Line | Kind | Source |
+				| ---- | ---: | ---- | ------ |
+				| CODEOWNERS | 2 | Shadowed rule | `+"`*.md @writers`"+` |
+
+			`), string(got))
+		})
+	}
+}
+
+func TestLintCommand_flags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "json jq",
+			args: []string{"--json", "--jq", "."},
+		},
+		{
+			name: "format json jq",
+			args: []string{"--format", "json", "--jq", "."},
+		},
+		{
+			name: "format json (mixed case) jq",
+			args: []string{"--format", "JSON", "--jq", "."},
+		},
+		{
+			name: "format json template",
+			args: []string{"--format", "json", "--template", "{{.}}"},
+		},
+		{
+			name:    "jq",
+			args:    []string{"--jq", "."},
+			wantErr: "cannot use `--jq` without specifying `--json`",
+		},
+		{
+			name:    "format sarif template",
+			args:    []string{"--format", "sarif", "--template", "{{.}}"},
+			wantErr: "cannot use `--template` without specifying `--json`",
+		},
+		{
+			name:    "fix jq",
+			args:    []string{"--fix", "--jq", "."},
+			wantErr: "cannot use `--jq` without specifying `--json`",
+		},
+		{
+			name:    "unknown owners json",
+			args:    []string{"--unknown-owners", "--json", "--jq", "."},
+			wantErr: "if any flags in the group [fix json unknown-owners] are set none of the others can be; [json unknown-owners] were all set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := LintCommand(&GlobalOptions{})
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			err := cmd.ValidateFlagGroups()
+			if err == nil {
+				err = cmd.PreRunE(cmd, nil)
+			}
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLintCommand_format(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+
+	fs := fstest.MapFS{
+		"CODEOWNERS":     {Data: []byte("*.md @writers\ndocs/ @writers\n")},
+		"docs/README.md": {Data: []byte("# README")},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStdout string
+	}{
+		{
+			name: "checkstyle",
+			args: []string{"--format", "CheckStyle"},
+			wantStdout: heredoc.Doc(`
+				<?xml version="1.0" encoding="UTF-8"?>
+				<checkstyle version="4.3">
+				  <file name="CODEOWNERS">
+				    <error line="1" column="1" severity="warning" message="Shadowed rule on line 1: every file matched by *.md is also matched by line(s) 2" source="gh-codeowners.shadowed-rule"></error>
+				  </file>
+				</checkstyle>
+			`),
+		},
+		{
+			name:       "json",
+			args:       []string{"--format", "JSON", "--jq", "[.[].kind]"},
+			wantStdout: "[\"Shadowed rule\"]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake()
+			cmd := LintCommand(&GlobalOptions{
+				Console: fake,
+
+				colorDisabled: true,
+				fs:            fs,
+			})
+			cmd.SetArgs(append([]string{"--offline", "--shadowed-rules"}, tt.args...))
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			var foundErr *FoundError
+			require.ErrorAs(t, err, &foundErr)
+
+			stdout, _, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
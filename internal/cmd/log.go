@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/heaths/gh-codeowners/internal/git"
+	"github.com/spf13/cobra"
+)
+
+const (
+	ownersAtCommit = "commit"
+	ownersAtTip    = "tip"
+)
+
+func LogCommand(globalOpts *GlobalOptions) *cobra.Command {
+	opts := &logOptions{
+		GlobalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "log <revision-range>",
+		Short: "Shows which owners changed files in a range of commits",
+		Long: "Attributes every file changed in a range of commits e.g., v1.0..v1.1 to its owners, " +
+			"using the CODEOWNERS file as of each commit or as of the newest commit in the range.\n\n" +
+			"The number of commits and files for each owner are shown as a table in a terminal, " +
+			"or each commit and its files are shown as JSON when piped to another program.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			opts.revRange = args[0]
+			return logOwners(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Show owners and commits as JSON.")
+	opts.addExportFlags(cmd, func() bool { return opts.json })
+	StringEnumVarP(cmd, &opts.ownersAt, "owners-at", "", ownersAtCommit, []string{ownersAtCommit, ownersAtTip}, "Use CODEOWNERS as of each commit or the tip of the range")
+
+	return cmd
+}
+
+type logOptions struct {
+	*GlobalOptions
+
+	json     bool
+	ownersAt string
+	revRange string
+}
+
+type logResult struct {
+	Owners  []ownerActivity `json:"owners"`
+	Commits []logCommit     `json:"commits"`
+}
+
+type ownerActivity struct {
+	Owner   string `json:"owner"`
+	Commits int    `json:"commits"`
+	Files   int    `json:"files"`
+}
+
+type logCommit struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+	Files   []file `json:"files"`
+}
+
+func logOwners(opts *logOptions) (err error) {
+	root, err := opts.RootDir()
+	if err != nil {
+		return
+	}
+
+	commits, err := git.Log(root, opts.revRange)
+	if err != nil {
+		return
+	}
+
+	var c *codeowners.Codeowners
+	if opts.ownersAt == ownersAtTip && len(commits) > 0 {
+		c, err = openCodeownersOrEmpty(opts.GlobalOptions, git.RefFS(root, commits[0].SHA))
+		if err != nil {
+			return
+		}
+	}
+
+	result := logResult{
+		Owners:  []ownerActivity{},
+		Commits: make([]logCommit, 0, len(commits)),
+	}
+
+	type activity struct {
+		ownerActivity
+		files map[string]bool
+	}
+	activities := newOwnerIndex(func(owner string) *activity {
+		return &activity{
+			ownerActivity: ownerActivity{Owner: owner},
+			files:         make(map[string]bool),
+		}
+	})
+
+	changed := make(map[string]bool)
+	unowned := make(map[string]bool)
+
+	for _, commit := range commits {
+		owners := c
+		if opts.ownersAt == ownersAtCommit {
+			owners, err = openCodeownersOrEmpty(opts.GlobalOptions, git.RefFS(root, commit.SHA))
+			if err != nil {
+				return
+			}
+		}
+
+		lc := logCommit{
+			SHA:     commit.SHA,
+			Subject: commit.Subject,
+			Files:   make([]file, 0, len(commit.Files)),
+		}
+
+		seen := make(map[*activity]bool)
+		for _, f := range commit.Files {
+			changed[f.Path] = true
+
+			fileOwners := owners.Owners(f.Path)
+			if len(fileOwners) == 0 {
+				unowned[f.Path] = true
+			}
+
+			for _, owner := range fileOwners {
+				a := activities.get(owner)
+
+				if !seen[a] {
+					seen[a] = true
+					a.Commits++
+				}
+
+				if !a.files[f.Path] {
+					a.files[f.Path] = true
+					a.Files++
+				}
+			}
+
+			lc.Files = append(lc.Files, file{
+				Path:       f.Path,
+				ChangeType: statusChangeType(f.Status),
+				Owners:     fileOwners,
+			})
+		}
+
+		result.Commits = append(result.Commits, lc)
+	}
+
+	for _, a := range activities.values {
+		result.Owners = append(result.Owners, a.ownerActivity)
+	}
+
+	sort.SliceStable(result.Owners, func(i, j int) bool {
+		a, b := result.Owners[i], result.Owners[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return strings.ToLower(a.Owner) < strings.ToLower(b.Owner)
+	})
+
+	if opts.json || opts.isExporting() || !opts.Console.IsStdoutTTY() {
+		return printJson(opts.GlobalOptions, result)
+	}
+
+	w := opts.Console.Stdout()
+	fmt.Fprintf(w, "%s changed %s owned by %s in %s\n\n",
+		plural(len(commits), "commit"),
+		plural(len(changed), "file"),
+		plural(len(result.Owners), "owner"),
+		opts.revRange,
+	)
+
+	tp := newTablePrinter(opts.GlobalOptions)
+	for _, header := range []string{"OWNER", "COMMITS", "FILES"} {
+		tp.AddField(header)
+	}
+	tp.EndRow()
+
+	for _, a := range result.Owners {
+		tp.AddField(a.Owner)
+		tp.AddField(strconv.Itoa(a.Commits))
+		tp.AddField(strconv.Itoa(a.Files))
+		tp.EndRow()
+	}
+
+	if err = tp.Render(); err != nil {
+		return
+	}
+
+	if len(unowned) > 0 {
+		fmt.Fprintf(w, "\n%s changed without owners\n", plural(len(unowned), "file"))
+	}
+
+	return
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/heaths/gh-codeowners/internal/gittest"
+	"github.com/heaths/go-console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	root := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": "* @heaths\n",
+		"main.go":    "package main\n",
+		"docs/a.md":  "# A\n",
+	})
+
+	gittest.Run(t, root, "tag", "v1")
+
+	commit := func(message string, files map[string]string) {
+		t.Helper()
+		gittest.WriteFiles(t, root, files)
+		gittest.Commit(t, root, message)
+	}
+
+	commit("Update docs", map[string]string{
+		"docs/a.md": "# A\n\nUpdated.\n",
+	})
+	commit("Add writers", map[string]string{
+		"CODEOWNERS": "* @heaths\ndocs/ @writers\n",
+		"main.go":    "package main\n\nfunc main() {}\n",
+	})
+
+	tests := []struct {
+		name       string
+		ownersAt   string
+		wantStdout string
+	}{
+		{
+			name:     "commit",
+			ownersAt: ownersAtCommit,
+			wantStdout: heredoc.Doc(`
+				2 commits changed 3 files owned by 1 owner in v1..HEAD
+
+				OWNER    COMMITS  FILES
+				@heaths  2        3
+			`),
+		},
+		{
+			name:     "tip",
+			ownersAt: ownersAtTip,
+			wantStdout: heredoc.Doc(`
+				2 commits changed 3 files owned by 2 owners in v1..HEAD
+
+				OWNER     COMMITS  FILES
+				@heaths   1        2
+				@writers  1        1
+			`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake(
+				console.WithStdoutTTY(true),
+				console.WithSize(80, 24),
+			)
+			opts := logOptions{
+				GlobalOptions: &GlobalOptions{
+					Console: fake,
+
+					colorDisabled: true,
+					root:          root,
+				},
+				ownersAt: tt.ownersAt,
+				revRange: "v1..HEAD",
+			}
+
+			err := logOwners(&opts)
+			require.NoError(t, err)
+
+			stdout, _, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+
+	t.Run("tip (mixed case)", func(t *testing.T) {
+		fake := console.Fake(
+			console.WithStdoutTTY(true),
+			console.WithSize(80, 24),
+		)
+		cmd := LogCommand(&GlobalOptions{
+			Console: fake,
+
+			colorDisabled: true,
+			root:          root,
+		})
+		cmd.SetArgs([]string{"--owners-at", "TIP", "v1..HEAD"})
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		stdout, _, _ := fake.Buffers()
+		assert.Equal(t, tests[1].wantStdout, stdout.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		fake := console.Fake()
+		opts := logOptions{
+			GlobalOptions: &GlobalOptions{
+				Console: fake,
+
+				colorDisabled: true,
+				root:          root,
+			},
+			ownersAt: ownersAtTip,
+			revRange: "v1..HEAD",
+		}
+
+		err := logOwners(&opts)
+		require.NoError(t, err)
+
+		stdout, _, _ := fake.Buffers()
+		var got logResult
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+
+		assert.Equal(t, []ownerActivity{
+			{Owner: "@heaths", Commits: 1, Files: 2},
+			{Owner: "@writers", Commits: 1, Files: 1},
+		}, got.Owners)
+
+		require.Len(t, got.Commits, 2)
+		assert.Equal(t, "Add writers", got.Commits[0].Subject)
+		assert.ElementsMatch(t, []file{
+			{Path: "CODEOWNERS", ChangeType: "MODIFIED", Owners: []string{"@heaths"}},
+			{Path: "main.go", ChangeType: "MODIFIED", Owners: []string{"@heaths"}},
+		}, got.Commits[0].Files)
+		assert.Equal(t, "Update docs", got.Commits[1].Subject)
+		assert.Equal(t, []file{
+			{Path: "docs/a.md", ChangeType: "MODIFIED", Owners: []string{"@writers"}},
+		}, got.Commits[1].Files)
+	})
+}
@@ -4,14 +4,20 @@ import (
 	"fmt"
 	"io/fs"
 	"log"
+	"os"
+	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/cli/go-gh"
+	"github.com/cli/go-gh/pkg/api"
 	"github.com/cli/go-gh/pkg/auth"
 	"github.com/cli/go-gh/pkg/repository"
 	"github.com/cli/go-gh/pkg/term"
+	"github.com/heaths/gh-codeowners/internal/codeowners"
 	"github.com/heaths/gh-codeowners/internal/git"
+	"github.com/heaths/gh-codeowners/internal/remote"
 	"github.com/heaths/go-console"
 	"github.com/spf13/cobra"
 )
@@ -19,20 +25,32 @@ import (
 type GlobalOptions struct {
 	Color   ColorOptions
 	Console console.Console
+	Dialect codeowners.Dialect
 	Log     *log.Logger
+	Ref     string
+	Remote  bool
 	Repo    repository.Repository
 	Verbose bool
 
+	jq        string
+	pushedSHA string
+	refSHA    string
+	remoteFS  *remote.FS
+	rootFS    fs.FS
+	template  string
+
 	// Test-only options.
 	host          string
 	authToken     string
 	colorDisabled bool
 	fs            fs.FS
+	root          string
 }
 
 type ColorOptions struct {
 	Comment string
 	Error   string
+	Section string
 }
 
 func (opts *GlobalOptions) EnsureRepository() (err error) {
@@ -44,7 +62,7 @@ func (opts *GlobalOptions) EnsureRepository() (err error) {
 	}
 
 	if opts.Repo == nil {
-		return fmt.Errorf("no repository")
+		return newUsageError("no repository")
 	}
 
 	return
@@ -59,7 +77,7 @@ func (opts *GlobalOptions) IsAuthenticated() error {
 
 	token, _ := auth.TokenForHost(host)
 	if token == "" {
-		return fmt.Errorf("use `gh auth login` to authenticate with required scopes")
+		return &ClientError{Err: fmt.Errorf("use `gh auth login` to authenticate with required scopes")}
 	}
 
 	return nil
@@ -72,15 +90,399 @@ func (opts *GlobalOptions) IsColorEnabled() bool {
 		opts.Console.IsStdoutTTY()
 }
 
-func (opts *GlobalOptions) RootFS() (fs.FS, error) {
-	if opts.fs == nil {
+func (opts *GlobalOptions) GQLClient() (api.GQLClient, error) {
+	client, err := gh.GQLClient(opts.clientOptions())
+	if err != nil {
+		return nil, &ClientError{Err: err}
+	}
+	return client, nil
+}
+
+func (opts *GlobalOptions) RESTClient() (api.RESTClient, error) {
+	client, err := gh.RESTClient(opts.clientOptions())
+	if err != nil {
+		return nil, &ClientError{Err: err}
+	}
+	return client, nil
+}
+
+func (opts *GlobalOptions) clientOptions() *api.ClientOptions {
+	clientOpts := &api.ClientOptions{
+		Host:      opts.host,
+		AuthToken: opts.authToken,
+	}
+	if clientOpts.Host == "" && opts.Repo != nil {
+		clientOpts.Host = opts.Repo.Host()
+	}
+
+	return clientOpts
+}
+
+func (opts *GlobalOptions) RootDir() (string, error) {
+	if opts.Remote {
+		return "", fmt.Errorf("no working tree for remote repository %s", repoName(opts.Repo))
+	}
+
+	if opts.root == "" {
 		var err error
-		opts.fs, err = git.RootFS()
+		opts.root, err = git.RootDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	return opts.root, nil
+}
+
+// RepoPath returns the slash-separated path relative to the repository root for a path relative to the current directory,
+// like git path arguments. Without a working tree, paths are already relative to the repository root.
+func (opts *GlobalOptions) RepoPath(p string) (string, error) {
+	if opts.Remote || opts.root == "" && opts.fs != nil {
+		return path.Clean(filepath.ToSlash(p)), nil
+	}
+
+	root, err := opts.RootDir()
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(p) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		p = filepath.Join(wd, p)
+	}
+
+	// The root from git has symlinks resolved, so resolve them in the current directory as well.
+	abs := evalSymlinks(filepath.Clean(p))
+	rel, err := filepath.Rel(evalSymlinks(root), abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", newUsageError("%s is outside repository at %s", p, root)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// evalSymlinks resolves symlinks in the longest existing parent of p, since p itself may not exist.
+func evalSymlinks(p string) string {
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+
+	dir, base := filepath.Split(p)
+	dir = filepath.Clean(dir)
+	if dir == p {
+		return p
+	}
+
+	return filepath.Join(evalSymlinks(dir), base)
+}
+
+// RootFS returns the working tree, or the tree at Ref if set.
+// For remote repositories, the tree at Ref or the default branch is read using the GitHub API.
+func (opts *GlobalOptions) RootFS() (fs.FS, error) {
+	if opts.fs != nil {
+		return opts.fs, nil
+	}
+
+	if opts.rootFS == nil {
+		fs, err := opts.openRootFS()
+		if err != nil {
+			return nil, err
+		}
+		opts.rootFS = fs
+	}
+
+	return opts.rootFS, nil
+}
+
+func (opts *GlobalOptions) openRootFS() (fs.FS, error) {
+	if opts.Remote {
+		return opts.remote()
+	}
+
+	root, err := opts.RootDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Ref != "" {
+		sha, err := opts.resolveRef()
+		if err != nil {
+			return nil, err
+		}
+		return git.RefFS(root, sha), nil
+	}
+
+	return os.DirFS(root), nil
+}
+
+// Files returns the slash-separated paths of files tracked in the repository.
+func (opts *GlobalOptions) Files() ([]string, error) {
+	if opts.fs != nil {
+		return codeowners.ListFiles(opts.fs)
+	}
+
+	if opts.Remote {
+		// List all files with a single request instead of a query for each directory.
+		ref, err := opts.RefName()
+		if err != nil {
+			return nil, err
+		}
+		return opts.treeFiles(ref)
+	}
+
+	root, err := opts.RootDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Ref != "" {
+		sha, err := opts.resolveRef()
+		if err != nil {
+			return nil, err
+		}
+		return git.ListTree(root, sha)
+	}
+
+	return git.ListFiles(root)
+}
+
+// treeFS returns the tree at ref from the local repository, or from GitHub for remote repositories.
+func (opts *GlobalOptions) treeFS(ref string) (fs.FS, error) {
+	if opts.Remote {
+		client, err := opts.GQLClient()
 		if err != nil {
 			return nil, err
 		}
+		return remote.New(client, opts.Repo, ref), nil
 	}
-	return opts.fs, nil
+
+	root, err := opts.RootDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sha, err := git.ResolveRef(root, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return git.RefFS(root, sha), nil
+}
+
+// treeFiles returns the slash-separated paths of files in the tree at ref.
+func (opts *GlobalOptions) treeFiles(ref string) ([]string, error) {
+	if opts.Remote {
+		files, err := opts.remoteFiles(ref)
+		if err != nil {
+			return nil, err
+		}
+		return filePaths(files), nil
+	}
+
+	root, err := opts.RootDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return git.ListTree(root, ref)
+}
+
+// remoteFiles returns the files in the remote repository at ref with their sizes.
+func (opts *GlobalOptions) remoteFiles(ref string) ([]remote.File, error) {
+	client, err := opts.GQLClient()
+	if err != nil {
+		return nil, err
+	}
+
+	rest, err := opts.RESTClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return remote.New(client, opts.Repo, ref).Files(rest)
+}
+
+func filePaths(files []remote.File) []string {
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	return paths
+}
+
+// RefName returns the ref to query, which is Ref if set, the default branch of a remote repository, or HEAD.
+func (opts *GlobalOptions) RefName() (string, error) {
+	if opts.Remote {
+		fs, err := opts.remote()
+		if err != nil {
+			return "", err
+		}
+		return fs.Ref()
+	}
+
+	if opts.Ref != "" {
+		// Query the same commit that RootFS reads.
+		return opts.resolveRef()
+	}
+
+	if opts.pushedSHA == "" {
+		sha, err := opts.pushedRef()
+		if err != nil {
+			return "", err
+		}
+		opts.pushedSHA = sha
+	}
+
+	return opts.pushedSHA, nil
+}
+
+// pushedRef returns HEAD, or the nearest ancestor pushed to its upstream branch since GitHub cannot find unpushed commits.
+// Whether HEAD was pushed is determined from local remote-tracking branches without querying GitHub.
+func (opts *GlobalOptions) pushedRef() (string, error) {
+	root, err := opts.RootDir()
+	if err != nil {
+		return "", err
+	}
+
+	head, err := git.ResolveRef(root, "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	pushed, err := git.IsPushed(root, head)
+	if err != nil || pushed {
+		return head, err
+	}
+
+	w := opts.Console.Stderr()
+	base, err := git.MergeBase(root, "HEAD")
+	if err != nil {
+		fmt.Fprintf(w, "warning: HEAD %s has not been pushed and has no upstream branch; pass --offline to check for errors locally\n", shortSHA(head))
+		return head, nil
+	}
+
+	fmt.Fprintf(w, "warning: HEAD %s has not been pushed; checking for errors at %s instead\n", shortSHA(head), shortSHA(base))
+
+	fs, err := opts.RootFS()
+	if err != nil {
+		return "", err
+	}
+
+	if path := codeowners.Find(fs, opts.codeownersOptions()...); path != "" {
+		differs, err := git.Differs(root, base, head, path)
+		if err != nil {
+			return "", err
+		}
+
+		if differs {
+			fmt.Fprintf(w, "warning: %s differs between %s and HEAD so errors may not match; pass --offline to check for errors locally\n", path, shortSHA(base))
+		}
+	}
+
+	return base, nil
+}
+
+func (opts *GlobalOptions) resolveRef() (string, error) {
+	if opts.refSHA == "" {
+		root, err := opts.RootDir()
+		if err != nil {
+			return "", err
+		}
+
+		opts.refSHA, err = git.ResolveRef(root, opts.Ref)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return opts.refSHA, nil
+}
+
+func (opts *GlobalOptions) remote() (*remote.FS, error) {
+	if opts.remoteFS == nil {
+		client, err := opts.GQLClient()
+		if err != nil {
+			return nil, err
+		}
+		opts.remoteFS = remote.New(client, opts.Repo, opts.Ref)
+	}
+
+	return opts.remoteFS, nil
+}
+
+// Codeowners finds and opens the CODEOWNERS file from RootFS.
+func (opts *GlobalOptions) Codeowners() (*codeowners.Codeowners, error) {
+	fs, err := opts.RootFS()
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.codeownersFS(fs)
+}
+
+// codeownersFS finds and opens the CODEOWNERS file from fs.
+func (opts *GlobalOptions) codeownersFS(fs fs.FS) (*codeowners.Codeowners, error) {
+	path := codeowners.Find(fs, opts.codeownersOptions()...)
+	if path == "" {
+		return nil, &UsageError{Err: codeowners.ErrNotFound}
+	}
+
+	return codeowners.Open(fs, path, opts.codeownersOptions()...)
+}
+
+func (opts *GlobalOptions) addRefFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&opts.Ref, "ref", "", "Use the CODEOWNERS file from a branch, tag, or commit instead of the working tree.")
+}
+
+// addExportFlags adds --jq and --template flags to filter or format JSON output.
+// Like gh, either flag requires json to return true e.g., when --json is passed.
+func (opts *GlobalOptions) addExportFlags(cmd *cobra.Command, json func() bool) {
+	cmd.Flags().StringVarP(&opts.jq, "jq", "q", "", "Filter JSON output using a jq `expression`.")
+	cmd.Flags().StringVarP(&opts.template, "template", "t", "", "Format JSON output using a Go template; see \"gh help formatting\".")
+	cmd.MarkFlagsMutuallyExclusive("jq", "template")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		if json() {
+			return nil
+		}
+
+		for _, name := range []string{"jq", "template"} {
+			if cmd.Flags().Changed(name) {
+				return newUsageError("cannot use `--%s` without specifying `--json`", name)
+			}
+		}
+
+		return nil
+	}
+}
+
+// isExporting returns whether JSON output will be filtered or formatted.
+func (opts *GlobalOptions) isExporting() bool {
+	return opts.jq != "" || opts.template != ""
+}
+
+func (opts *GlobalOptions) codeownersOptions() []codeowners.Option {
+	return []codeowners.Option{
+		codeowners.WithDialect(opts.Dialect),
+	}
+}
+
+// WriteFile writes data to the named file relative to the repository root, retaining its permissions.
+func (opts *GlobalOptions) WriteFile(name string, data []byte) error {
+	root, err := opts.RootDir()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(root, filepath.FromSlash(name))
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, stat.Mode().Perm())
 }
 
 func StringEnumVarP(cmd *cobra.Command, p *string, name, shorthand, defaultValue string, values []string, usage string) {
@@ -105,12 +507,16 @@ func (v *enumValue) String() string {
 	return *v.value
 }
 
+// Set stores the value matching s case-insensitively as it was declared, so commands can compare it to constants.
 func (v *enumValue) Set(s string) error {
-	if !stringSliceContains(s, v.values) {
-		return fmt.Errorf("valid values are {%s}", strings.Join(v.values, "|"))
+	for _, value := range v.values {
+		if strings.EqualFold(s, value) {
+			*v.value = value
+			return nil
+		}
 	}
-	*v.value = s
-	return nil
+
+	return fmt.Errorf("valid values are {%s}", strings.Join(v.values, "|"))
 }
 
 func (v *enumValue) Type() string {
@@ -127,10 +533,24 @@ func stringSliceContains(value string, values []string) bool {
 	return false
 }
 
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func repoName(repo repository.Repository) string {
+	if repo == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", repo.Owner(), repo.Name())
+}
+
 func parseNumberRef(number string) (int, error) {
 	number = strings.TrimPrefix(number, "#")
 	if i, err := strconv.ParseInt(number, 10, 32); err != nil {
-		return 0, fmt.Errorf("parse issue or pull request number: %w", err)
+		return 0, newUsageError("parse issue or pull request number: %w", err)
 	} else {
 		return int(i), nil
 	}
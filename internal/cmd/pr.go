@@ -2,10 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"io/fs"
+	"strings"
 
-	"github.com/cli/go-gh"
-	"github.com/cli/go-gh/pkg/api"
 	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/heaths/gh-codeowners/internal/remote"
 	"github.com/shurcooL/graphql"
 	"github.com/spf13/cobra"
 )
@@ -18,8 +19,12 @@ func PrCommand(globalOpts *GlobalOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "pr",
 		Short: "Views the owners for a list of files in a pull request",
-		Long:  "Shows the owners for each file in a pull request. You must be in a repository to find the CODEOWNERS file.",
-		Args:  cobra.ExactArgs(1),
+		Long: "Shows the owners for each file in a pull request and whether each owner approved, was requested to review, or is missing. " +
+			"Team owners have approved when any member of the team approved, and approval from owners specified by email address is unknown.\n\n" +
+			"Like GitHub, owners are determined by the CODEOWNERS file on the pull request's base branch. " +
+			"Pass --use-local to use your CODEOWNERS file instead e.g., to try out changes before pushing them. " +
+			"Files are grouped by owner in a terminal or shown as JSON when piped to another program.",
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			err = opts.EnsureRepository()
 			if err != nil {
@@ -40,45 +45,34 @@ func PrCommand(globalOpts *GlobalOptions) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Show files as JSON.")
+	opts.addExportFlags(cmd, func() bool { return opts.json })
+	cmd.Flags().BoolVar(&opts.useLocal, "use-local", false, "Use the local CODEOWNERS file instead of the base branch's.")
+
 	return cmd
 }
 
 type prOptions struct {
 	*GlobalOptions
 
-	number int
+	json     bool
+	number   int
+	useLocal bool
 }
 
 func pr(opts *prOptions) (err error) {
-	clientOpts := &api.ClientOptions{
-		Host:      opts.host,
-		AuthToken: opts.authToken,
-	}
-	client, err := gh.GQLClient(clientOpts)
+	client, err := opts.GQLClient()
 	if err != nil {
 		return
 	}
 
-	fs, err := opts.RootFS()
-	if err != nil {
-		return err
-	}
-
-	path := codeowners.Find(fs)
-	if path == "" {
-		return fmt.Errorf("CODEOWNERS not found")
-	}
-
-	var c *codeowners.Codeowners
-	c, err = codeowners.Open(fs, path)
-	if err != nil {
-		return err
-	}
-
 	var query struct {
 		Repository struct {
 			PullRequest struct {
-				Files struct {
+				BaseRefName string
+				BaseRefOid  string
+				HeadRefOid  string
+				Files       struct {
 					Nodes []struct {
 						Path       string
 						ChangeType string
@@ -110,7 +104,6 @@ func pr(opts *prOptions) (err error) {
 			files = append(files, file{
 				Path:       node.Path,
 				ChangeType: node.ChangeType,
-				Owners:     c.Owners(node.Path),
 			})
 		}
 
@@ -121,11 +114,211 @@ func pr(opts *prOptions) (err error) {
 		}
 	}
 
-	return printJson(opts.GlobalOptions, files)
+	// Read the base commit instead of the branch, which may have changed since the pull request was updated.
+	base := remote.New(client, opts.Repo, query.Repository.PullRequest.BaseRefOid)
+
+	var c *codeowners.Codeowners
+	if opts.useLocal {
+		c, err = opts.Codeowners()
+	} else {
+		c, err = opts.codeownersFS(base)
+	}
+	if err != nil {
+		return
+	}
+
+	r, err := queryReviews(client, opts.Repo, opts.number)
+	if err != nil {
+		return
+	}
+
+	changed := -1
+	for i := range files {
+		files[i].Owners = c.Owners(files[i].Path)
+		files[i].Approvals, err = r.approvals(files[i].Owners)
+		if err != nil {
+			return
+		}
+		files[i].Approval = fileApproval(files[i].Approvals)
+
+		if changed < 0 && isCodeowners(opts.GlobalOptions, files[i].Path) {
+			changed = i
+		}
+	}
+
+	// Only one CODEOWNERS file is used, so compare owners once and show changes with the first CODEOWNERS file changed.
+	if changed >= 0 {
+		head := remote.New(client, opts.Repo, query.Repository.PullRequest.HeadRefOid)
+		files[changed].OwnershipChanges, err = ownershipChanges(opts, base, head)
+		if err != nil {
+			return
+		}
+	}
+
+	if opts.json || opts.isExporting() || !opts.Console.IsStdoutTTY() {
+		return printJson(opts.GlobalOptions, files)
+	}
+
+	baseRefName := query.Repository.PullRequest.BaseRefName
+	if opts.useLocal {
+		baseRefName = ""
+	}
+
+	printFilesByOwner(opts, files, baseRefName)
+	return
+}
+
+func printFilesByOwner(opts *prOptions, files []file, baseRefName string) {
+	groups, unowned := groupByOwner(files)
+
+	w := opts.Console.Stdout()
+	fmt.Fprintf(w, "Showing %s owned by %s in #%d", plural(len(files), "file"), plural(len(groups), "owner"), opts.number)
+	if baseRefName != "" {
+		fmt.Fprintf(w, " using CODEOWNERS from %s", baseRefName)
+	}
+	fmt.Fprintln(w)
+
+	approvals := make(map[string]string)
+	for _, f := range files {
+		for _, a := range f.Approvals {
+			approvals[strings.ToLower(a.Owner)] = a.Approval
+		}
+	}
+
+	printOwnerGroups(opts.GlobalOptions, groups, unowned, func(owner string) string {
+		return approval(opts.GlobalOptions, approvals[strings.ToLower(owner)])
+	})
+
+	for _, f := range files {
+		if len(f.OwnershipChanges) > 0 {
+			fmt.Fprintf(w, "\nOwnership changes in %s (%s)\n", f.Path, plural(len(f.OwnershipChanges), "file"))
+			printOwnershipChanges(opts.GlobalOptions, f.OwnershipChanges)
+		}
+	}
+
+	// Show which owners of each file, any one of whom must approve, are still blocking the pull request.
+	var waiting, unknown []file
+	for _, f := range files {
+		switch f.Approval {
+		case approvalMissing, approvalReviewRequested:
+			waiting = append(waiting, f)
+		case approvalUnknown:
+			unknown = append(unknown, f)
+		}
+	}
+
+	if len(waiting) > 0 {
+		fmt.Fprintf(w, "\nWaiting on code owner approval for %s\n", plural(len(waiting), "file"))
+		for _, g := range groupByRuleOwners(waiting) {
+			fmt.Fprintf(w, "%s%s (%s): %s\n", indent, strings.Join(g.owners, " or "), plural(len(g.files), "file"), approval(opts.GlobalOptions, g.files[0].Approval))
+		}
+	}
+
+	if len(unknown) > 0 {
+		fmt.Fprintf(w, "\nCannot determine code owner approval by email for %s\n", plural(len(unknown), "file"))
+	}
+}
+
+// ruleOwners are the owners of files matched by the same rule, any one of whom can approve changes.
+type ruleOwners struct {
+	owners []string
+	files  []file
+}
+
+// groupByRuleOwners groups files with the same owners in the order they were first found.
+func groupByRuleOwners(files []file) []ruleOwners {
+	var groups []ruleOwners
+	index := make(map[string]int)
+
+	for _, f := range files {
+		key := strings.ToLower(strings.Join(f.Owners, " "))
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ruleOwners{owners: f.Owners})
+		}
+		groups[i].files = append(groups[i].files, f)
+	}
+
+	return groups
+}
+
+// isCodeowners returns whether path is where GitHub looks for a CODEOWNERS file.
+func isCodeowners(opts *GlobalOptions, path string) bool {
+	for _, location := range codeowners.Locations(opts.codeownersOptions()...) {
+		if path == location {
+			return true
+		}
+	}
+	return false
+}
+
+// ownershipChanges compares the owners of every file in the head commit using the base and head CODEOWNERS files.
+func ownershipChanges(opts *prOptions, base, head *remote.FS) ([]codeowners.Change, error) {
+	before, err := openCodeownersOrEmpty(opts.GlobalOptions, base)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := openCodeownersOrEmpty(opts.GlobalOptions, head)
+	if err != nil {
+		return nil, err
+	}
+
+	rest, err := opts.RESTClient()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := head.Files(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	return codeowners.Diff(before, after, filePaths(files)), nil
+}
+
+// openCodeownersOrEmpty opens the CODEOWNERS file in fs, or returns empty Codeowners if added or deleted.
+func openCodeownersOrEmpty(opts *GlobalOptions, fs fs.FS) (*codeowners.Codeowners, error) {
+	if codeowners.Find(fs, opts.codeownersOptions()...) == "" {
+		return codeowners.New(&codeowners.File{})
+	}
+
+	return opts.codeownersFS(fs)
+}
+
+// approval describes and colors an approval status.
+func approval(opts *GlobalOptions, status string) string {
+	text := strings.ToLower(strings.ReplaceAll(status, "_", " "))
+	switch status {
+	case approvalMissing:
+		text = "approval missing"
+	case approvalUnknown:
+		text = "approval unknown"
+	}
+
+	if !opts.IsColorEnabled() {
+		return text
+	}
+
+	cs := opts.Console.ColorScheme()
+	switch status {
+	case approvalApproved:
+		return cs.Green(text)
+	case approvalReviewRequested, approvalUnknown:
+		return cs.Yellow(text)
+	default:
+		return cs.Red(text)
+	}
 }
 
 type file struct {
 	Path       string   `json:"path"`
 	ChangeType string   `json:"changeType"`
 	Owners     []string `json:"owners"`
+	Approval   string   `json:"approval,omitempty"`
+
+	Approvals        []ownerApproval     `json:"approvals,omitempty"`
+	OwnershipChanges []codeowners.Change `json:"ownershipChanges,omitempty"`
 }
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"fmt"
 	"io/fs"
+	"strings"
 	"testing"
 	"testing/fstest"
 
@@ -19,15 +21,95 @@ func TestPR(t *testing.T) {
 		* @heaths
 		docs/ @writers
 	`)
+	codeownersChanged := func(paths ...string) func() {
+		return func() {
+			var nodes, entries []string
+			for _, path := range paths {
+				nodes = append(nodes, fmt.Sprintf(`{"path": %q, "changeType": "MODIFIED"}`, path))
+				if path != ".github/CODEOWNERS" {
+					entries = append(entries, fmt.Sprintf(`{"path": %q, "type": "blob"},`, path))
+				}
+			}
+
+			gock.New("https://api.github.com").
+				Post("/graphql").
+				BodyString(`PullRequestFiles`).
+				Reply(200).
+				JSON(`{
+				"data": {
+					"repository": {
+						"pullRequest": {
+							"baseRefName": "main",
+							"baseRefOid": "base",
+							"headRefOid": "head",
+							"files": {
+								"nodes": [` + strings.Join(nodes, ",") + `],
+								"pageInfo": {
+									"hasNextPage": false
+								}
+							}
+						}
+					}
+				}
+			}`)
+			gock.New("https://api.github.com").
+				Post("/graphql").
+				BodyString(`"expression":"base:.github/CODEOWNERS"`).
+				Reply(200).
+				JSON(`{
+				"data": {
+					"repository": {
+						"object": {
+							"__typename": "Blob",
+							"text": "* @heaths\nold/ @heaths\n"
+						}
+					}
+				}
+			}`)
+			gock.New("https://api.github.com").
+				Post("/graphql").
+				BodyString(`PullRequestReviews`).
+				Reply(200).
+				JSON(`{"data":{"repository":{"pullRequest":{"latestOpinionatedReviews":{"nodes":[]},"reviewRequests":{"nodes":[]}}}}}`)
+			gock.New("https://api.github.com").
+				Post("/graphql").
+				BodyString(`"expression":"head:.github/CODEOWNERS"`).
+				Reply(200).
+				JSON(`{
+				"data": {
+					"repository": {
+						"object": {
+							"__typename": "Blob",
+							"text": "* @heaths\nold/\ndocs/ @writers\n"
+						}
+					}
+				}
+			}`)
+			gock.New("https://api.github.com").
+				Get("/repos/heaths/gh-codeowners/git/trees/head").
+				Reply(200).
+				JSON(`{
+				"tree": [
+					{"path": ".github/CODEOWNERS", "type": "blob"},` + strings.Join(entries, "") + `
+					{"path": "docs/index.md", "type": "blob"},
+					{"path": "old/main.go", "type": "blob"}
+				]
+			}`)
+		}
+	}
+
 	tests := []struct {
 		name       string
 		tty        bool
+		json       bool
+		useLocal   bool
 		fs         fs.FS
 		mocks      func()
 		wantStdout string
 	}{
 		{
-			name: "single page",
+			name:     "single page",
+			useLocal: true,
 			fs: fstest.MapFS{
 				"CODEOWNERS": {Data: []byte(content)},
 			},
@@ -58,12 +140,19 @@ func TestPR(t *testing.T) {
 							}
 						}
 					}`)
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(`{"data":{"repository":{"pullRequest":{"latestOpinionatedReviews":{"nodes":[]},"reviewRequests":{"nodes":[]}}}}}`)
 			},
-			wantStdout: `[{"path":"main.go","changeType":"MODIFIED","owners":["@heaths"]},{"path":"docs/README.md","changeType":"ADDED","owners":["@writers"]}]`,
+			wantStdout: `[{"path":"main.go","changeType":"MODIFIED","owners":["@heaths"],"approval":"MISSING","approvals":[{"owner":"@heaths","approval":"MISSING"}]},` +
+				`{"path":"docs/README.md","changeType":"ADDED","owners":["@writers"],"approval":"MISSING","approvals":[{"owner":"@writers","approval":"MISSING"}]}]`,
 		},
 		{
-			name: "multiple pages (tty)",
-			tty:  true,
+			name:     "multiple pages (tty)",
+			tty:      true,
+			json:     true,
+			useLocal: true,
 			fs: fstest.MapFS{
 				"CODEOWNERS": {Data: []byte(content)},
 			},
@@ -113,6 +202,10 @@ func TestPR(t *testing.T) {
 							}
 						}
 					}`)
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(`{"data":{"repository":{"pullRequest":{"latestOpinionatedReviews":{"nodes":[]},"reviewRequests":{"nodes":[]}}}}}`)
 			},
 			wantStdout: heredoc.Doc(`
 				[
@@ -121,6 +214,13 @@ func TestPR(t *testing.T) {
 				    "changeType": "MODIFIED",
 				    "owners": [
 				      "@heaths"
+				    ],
+				    "approval": "MISSING",
+				    "approvals": [
+				      {
+				        "owner": "@heaths",
+				        "approval": "MISSING"
+				      }
 				    ]
 				  },
 				  {
@@ -128,11 +228,345 @@ func TestPR(t *testing.T) {
 				    "changeType": "ADDED",
 				    "owners": [
 				      "@writers"
+				    ],
+				    "approval": "MISSING",
+				    "approvals": [
+				      {
+				        "owner": "@writers",
+				        "approval": "MISSING"
+				      }
 				    ]
 				  }
 				]
 			`),
 		},
+		{
+			name:     "grouped by owner (tty)",
+			tty:      true,
+			useLocal: true,
+			fs: fstest.MapFS{
+				"CODEOWNERS": {Data: []byte(content + "*.md @heaths @writers\n*.txt @org/docs\nold/\n")},
+			},
+			mocks: func() {
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(`{
+						"data": {
+							"repository": {
+								"pullRequest": {
+									"files": {
+										"nodes": [
+											{
+												"path": "main.go",
+												"changeType": "MODIFIED"
+											},
+											{
+												"path": "docs/README.md",
+												"changeType": "ADDED"
+											},
+											{
+												"path": "docs/guide.txt",
+												"changeType": "RENAMED"
+											},
+											{
+												"path": "old/main.go",
+												"changeType": "DELETED"
+											}
+										],
+										"pageInfo": {
+											"hasNextPage": false
+										}
+									}
+								}
+							}
+						}
+					}`)
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(`{
+						"data": {
+							"repository": {
+								"pullRequest": {
+									"latestOpinionatedReviews": {
+										"nodes": [
+											{
+												"state": "APPROVED",
+												"author": {"login": "alice"}
+											},
+											{
+												"state": "CHANGES_REQUESTED",
+												"author": {"login": "writers"}
+											}
+										]
+									},
+									"reviewRequests": {
+										"nodes": [
+											{
+												"requestedReviewer": {"login": "heaths"}
+											}
+										]
+									}
+								}
+							}
+						}
+					}`)
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(`{
+						"data": {
+							"organization": {
+								"team": {
+									"members": {
+										"nodes": [
+											{"login": "bob"},
+											{"login": "alice"}
+										],
+										"pageInfo": {
+											"hasNextPage": false
+										}
+									}
+								}
+							}
+						}
+					}`)
+			},
+			wantStdout: heredoc.Doc(`
+				Showing 4 files owned by 3 owners in #1
+
+				@heaths (2 files): review requested
+				  M  main.go
+				  A  docs/README.md
+
+				@org/docs (1 file): approved
+				  R  docs/guide.txt
+
+				@writers (1 file): approval missing
+				  A  docs/README.md
+
+				Unowned (1 file)
+				  D  old/main.go
+
+				Waiting on code owner approval for 2 files
+				  @heaths (1 file): review requested
+				  @heaths or @writers (1 file): review requested
+			`),
+		},
+		{
+			name:     "email owners and more reviews (tty)",
+			tty:      true,
+			useLocal: true,
+			fs: fstest.MapFS{
+				"CODEOWNERS": {Data: []byte("* @heaths\n*.md docs@example.com\n")},
+			},
+			mocks: func() {
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`PullRequestFiles`).
+					Reply(200).
+					JSON(`{
+						"data": {
+							"repository": {
+								"pullRequest": {
+									"files": {
+										"nodes": [
+											{
+												"path": "main.go",
+												"changeType": "MODIFIED"
+											},
+											{
+												"path": "README.md",
+												"changeType": "ADDED"
+											}
+										],
+										"pageInfo": {
+											"hasNextPage": false
+										}
+									}
+								}
+							}
+						}
+					}`)
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`"reviewsCursor":""`).
+					Reply(200).
+					JSON(`{
+						"data": {
+							"repository": {
+								"pullRequest": {
+									"latestOpinionatedReviews": {
+										"nodes": [],
+										"pageInfo": {
+											"hasNextPage": true,
+											"endCursor": "abcd1234"
+										}
+									},
+									"reviewRequests": {
+										"nodes": [],
+										"pageInfo": {
+											"hasNextPage": false
+										}
+									}
+								}
+							}
+						}
+					}`)
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`"reviewsCursor":"abcd1234"`).
+					Reply(200).
+					JSON(`{
+						"data": {
+							"repository": {
+								"pullRequest": {
+									"latestOpinionatedReviews": {
+										"nodes": [
+											{
+												"state": "APPROVED",
+												"author": {"login": "heaths"}
+											}
+										],
+										"pageInfo": {
+											"hasNextPage": false
+										}
+									},
+									"reviewRequests": {
+										"nodes": [],
+										"pageInfo": {
+											"hasNextPage": false
+										}
+									}
+								}
+							}
+						}
+					}`)
+			},
+			wantStdout: heredoc.Doc(`
+				Showing 2 files owned by 2 owners in #1
+
+				@heaths (1 file): approved
+				  M  main.go
+
+				docs@example.com (1 file): approval unknown
+				  A  README.md
+
+				Cannot determine code owner approval by email for 1 file
+			`),
+		},
+		{
+			name: "base branch (tty)",
+			tty:  true,
+			fs: fstest.MapFS{
+				"CODEOWNERS": {Data: []byte("* @nobody\n")},
+			},
+			mocks: func() {
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(`{
+						"data": {
+							"repository": {
+								"pullRequest": {
+									"baseRefName": "main",
+									"baseRefOid": "0123456789abcdef0123456789abcdef01234567",
+									"files": {
+										"nodes": [
+											{
+												"path": "docs/README.md",
+												"changeType": "MODIFIED"
+											}
+										],
+										"pageInfo": {
+											"hasNextPage": false
+										}
+									}
+								}
+							}
+						}
+					}`)
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`"expression":"0123456789abcdef0123456789abcdef01234567:.github/CODEOWNERS"`).
+					Reply(200).
+					JSON(`{"data":{"repository":{"object":null}}}`)
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					BodyString(`"expression":"0123456789abcdef0123456789abcdef01234567:CODEOWNERS"`).
+					Reply(200).
+					JSON(`{
+						"data": {
+							"repository": {
+								"object": {
+									"__typename": "Blob",
+									"byteSize": 25,
+									"text": "* @heaths\ndocs/ @writers\n"
+								}
+							}
+						}
+					}`)
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(`{"data":{"repository":{"pullRequest":{"latestOpinionatedReviews":{"nodes":[]},"reviewRequests":{"nodes":[]}}}}}`)
+			},
+			wantStdout: heredoc.Doc(`
+				Showing 1 file owned by 1 owner in #1 using CODEOWNERS from main
+
+				@writers (1 file): approval missing
+				  M  docs/README.md
+
+				Waiting on code owner approval for 1 file
+				  @writers (1 file): approval missing
+			`),
+		},
+		{
+			name:  "codeowners changed (tty)",
+			tty:   true,
+			mocks: codeownersChanged(".github/CODEOWNERS"),
+			wantStdout: heredoc.Doc(`
+				Showing 1 file owned by 1 owner in #1 using CODEOWNERS from main
+
+				@heaths (1 file): approval missing
+				  M  .github/CODEOWNERS
+
+				Ownership changes in .github/CODEOWNERS (2 files)
+				  changed  docs/index.md  @heaths → @writers
+				  lost     old/main.go    @heaths
+
+				Waiting on code owner approval for 1 file
+				  @heaths (1 file): approval missing
+			`),
+		},
+		{
+			name:  "codeowners changed",
+			mocks: codeownersChanged(".github/CODEOWNERS"),
+			wantStdout: `[{"path":".github/CODEOWNERS","changeType":"MODIFIED","owners":["@heaths"],"approval":"MISSING","approvals":[{"owner":"@heaths","approval":"MISSING"}],"ownershipChanges":[` +
+				`{"kind":"changed","path":"docs/index.md","oldOwners":["@heaths"],"newOwners":["@writers"]},` +
+				`{"kind":"lost","path":"old/main.go","oldOwners":["@heaths"],"newOwners":[]}]}]`,
+		},
+		{
+			name:  "multiple codeowners changed (tty)",
+			tty:   true,
+			mocks: codeownersChanged(".github/CODEOWNERS", "docs/CODEOWNERS"),
+			wantStdout: heredoc.Doc(`
+				Showing 2 files owned by 1 owner in #1 using CODEOWNERS from main
+
+				@heaths (2 files): approval missing
+				  M  .github/CODEOWNERS
+				  M  docs/CODEOWNERS
+
+				Ownership changes in .github/CODEOWNERS (3 files)
+				  changed  docs/CODEOWNERS  @heaths → @writers
+				  changed  docs/index.md    @heaths → @writers
+				  lost     old/main.go      @heaths
+
+				Waiting on code owner approval for 2 files
+				  @heaths (2 files): approval missing
+			`),
+		},
 	}
 
 	for _, tt := range tests {
@@ -156,6 +590,9 @@ func TestPR(t *testing.T) {
 					colorDisabled: true,
 					fs:            tt.fs,
 				},
+				json:     tt.json,
+				number:   1,
+				useLocal: tt.useLocal,
 			}
 
 			if tt.mocks != nil {
@@ -164,9 +601,54 @@ func TestPR(t *testing.T) {
 
 			err = pr(&opts)
 			require.NoError(t, err)
+			assert.True(t, gock.IsDone())
 
 			stdout, _, _ := fake.Buffers()
 			assert.Equal(t, tt.wantStdout, stdout.String())
 		})
 	}
 }
+
+func TestPR_teamMembersError(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`PullRequestFiles`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"pullRequest":{"files":{"nodes":[{"path":"main.go","changeType":"MODIFIED"}]}}}}}`)
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`PullRequestReviews`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"pullRequest":{"latestOpinionatedReviews":{"nodes":[{"state":"APPROVED","author":{"login":"alice"}}]},"reviewRequests":{"nodes":[]}}}}}`)
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`TeamMembers`).
+		Reply(200).
+		JSON(`{"data":{"organization":{"team":null}},"errors":[{"type":"INSUFFICIENT_SCOPES","message":"Your token has not been granted the required scopes."}]}`)
+
+	fake := console.Fake()
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := prOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+			Repo:    repo,
+
+			host:          "github.com",
+			authToken:     "***",
+			colorDisabled: true,
+			fs: fstest.MapFS{
+				"CODEOWNERS": {Data: []byte("* @org/docs\n")},
+			},
+		},
+		number:   1,
+		useLocal: true,
+	}
+
+	err = pr(&opts)
+	assert.ErrorContains(t, err, "failed to get members of @org/docs")
+	assert.True(t, gock.IsDone())
+}
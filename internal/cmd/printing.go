@@ -3,9 +3,22 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/cli/go-gh/pkg/jq"
 	"github.com/cli/go-gh/pkg/jsonpretty"
+	"github.com/cli/go-gh/pkg/tableprinter"
+	"github.com/cli/go-gh/pkg/template"
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/heaths/go-console/pkg/colorscheme"
+)
+
+const (
+	defaultWidth = 80
 )
 
 func printJson(opts *GlobalOptions, v any) error {
@@ -15,6 +28,26 @@ func printJson(opts *GlobalOptions, v any) error {
 	}
 
 	r := bytes.NewBuffer(buf)
+	if opts.jq != "" {
+		return jq.Evaluate(r, opts.Console.Stdout(), opts.jq)
+	}
+
+	if opts.template != "" {
+		width, _, err := opts.Console.Size()
+		if err != nil || width <= 0 {
+			width = defaultWidth
+		}
+
+		t := template.New(opts.Console.Stdout(), width, opts.IsColorEnabled())
+		if err := t.Parse(opts.template); err != nil {
+			return err
+		}
+		if err := t.Execute(r); err != nil {
+			return err
+		}
+		return t.Flush()
+	}
+
 	if opts.Console.IsStdoutTTY() {
 		return jsonpretty.Format(opts.Console.Stdout(), r, indent, opts.IsColorEnabled())
 	}
@@ -22,3 +55,180 @@ func printJson(opts *GlobalOptions, v any) error {
 	_, err = io.Copy(opts.Console.Stdout(), r)
 	return err
 }
+
+func newTablePrinter(opts *GlobalOptions) tableprinter.TablePrinter {
+	width, _, err := opts.Console.Size()
+	if err != nil || width <= 0 {
+		width = defaultWidth
+	}
+
+	return tableprinter.New(opts.Console.Stdout(), opts.Console.IsStdoutTTY(), width)
+}
+
+func printOwnershipChanges(opts *GlobalOptions, changes []codeowners.Change) {
+	width := 0
+	for _, change := range changes {
+		if n := utf8.RuneCountInString(change.Path); n > width {
+			width = n
+		}
+	}
+
+	var cs *colorscheme.ColorScheme
+	if opts.IsColorEnabled() {
+		cs = opts.Console.ColorScheme()
+	}
+
+	w := opts.Console.Stdout()
+	for _, change := range changes {
+		kind := fmt.Sprintf("%-7s", change.Kind)
+		if cs != nil {
+			switch change.Kind {
+			case codeowners.ChangeKindGained:
+				kind = cs.Green(kind)
+			case codeowners.ChangeKindLost:
+				kind = cs.Red(kind)
+			default:
+				kind = cs.Yellow(kind)
+			}
+		}
+
+		owners := strings.Join(change.NewOwners, " ")
+		switch change.Kind {
+		case codeowners.ChangeKindLost:
+			owners = strings.Join(change.OldOwners, " ")
+		case codeowners.ChangeKindChanged:
+			owners = strings.Join(change.OldOwners, " ") + " → " + owners
+		}
+
+		fmt.Fprintf(w, "%s%s  %-*s  %s\n", indent, kind, width, change.Path, owners)
+	}
+}
+
+type ownerGroup struct {
+	owner string
+	files []file
+}
+
+// ownerIndex indexes values by owner in the order owners were first found.
+// Owners are case-insensitive, so they are indexed by their lowercase name but values are created with the first name found.
+type ownerIndex[T any] struct {
+	create func(owner string) *T
+	index  map[string]*T
+	values []*T
+}
+
+func newOwnerIndex[T any](create func(owner string) *T) *ownerIndex[T] {
+	return &ownerIndex[T]{
+		create: create,
+		index:  make(map[string]*T),
+	}
+}
+
+// get returns the value for owner, creating it if owner was not yet found.
+func (i *ownerIndex[T]) get(owner string) *T {
+	key := strings.ToLower(owner)
+	v, ok := i.index[key]
+	if !ok {
+		v = i.create(owner)
+		i.index[key] = v
+		i.values = append(i.values, v)
+	}
+	return v
+}
+
+// groupByOwner groups files under each of their owners sorted by owner, and returns any unowned files separately.
+func groupByOwner(files []file) ([]ownerGroup, []file) {
+	owners := newOwnerIndex(func(owner string) *ownerGroup {
+		return &ownerGroup{owner: owner}
+	})
+	var unowned []file
+
+	for _, f := range files {
+		if len(f.Owners) == 0 {
+			unowned = append(unowned, f)
+			continue
+		}
+
+		for _, owner := range f.Owners {
+			g := owners.get(owner)
+			g.files = append(g.files, f)
+		}
+	}
+
+	groups := owners.values
+
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].owner) < strings.ToLower(groups[j].owner)
+	})
+
+	results := make([]ownerGroup, len(groups))
+	for i, g := range groups {
+		results[i] = *g
+	}
+
+	return results, unowned
+}
+
+// printOwnerGroups prints files with their change type under each owner, followed by any unowned files.
+// If status is not nil, its description of each owner is appended to their header.
+func printOwnerGroups(opts *GlobalOptions, groups []ownerGroup, unowned []file, status func(owner string) string) {
+	w := opts.Console.Stdout()
+	printGroup := func(header string, files []file) {
+		fmt.Fprintf(w, "\n%s\n", header)
+		for _, f := range files {
+			fmt.Fprintf(w, "%s%s  %s\n", indent, changeType(opts, f.ChangeType), f.Path)
+		}
+	}
+
+	for _, g := range groups {
+		header := fmt.Sprintf("%s (%s)", g.owner, plural(len(g.files), "file"))
+		if status != nil {
+			header += ": " + status(g.owner)
+		}
+		printGroup(header, g.files)
+	}
+
+	if len(unowned) > 0 {
+		printGroup(fmt.Sprintf("Unowned (%s)", plural(len(unowned), "file")), unowned)
+	}
+}
+
+// changeType abbreviates and colors a GitHub PatchStatus like git status.
+func changeType(opts *GlobalOptions, status string) string {
+	var abbr string
+	var color func(string) string
+
+	cs := opts.Console.ColorScheme()
+	switch status {
+	case "ADDED":
+		abbr, color = "A", cs.Green
+	case "CHANGED":
+		// GitHub reports a change to the file type e.g., to a symlink, which git abbreviates as "T".
+		abbr, color = "T", cs.Magenta
+	case "COPIED":
+		abbr, color = "C", cs.Blue
+	case "DELETED":
+		abbr, color = "D", cs.Red
+	case "MODIFIED":
+		abbr, color = "M", cs.Yellow
+	case "RENAMED":
+		abbr, color = "R", cs.Cyan
+	case changeTypeUntracked:
+		abbr, color = "?", cs.Green
+	default:
+		return "?"
+	}
+
+	if !opts.IsColorEnabled() {
+		return abbr
+	}
+
+	return color(abbr)
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/heaths/go-console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintJson(t *testing.T) {
+	v := []ownerStats{
+		{Owner: "@heaths", Files: 3},
+		{Owner: "@writers", Files: 2},
+	}
+
+	tests := []struct {
+		name       string
+		tty        bool
+		jq         string
+		template   string
+		wantStdout string
+		wantErr    bool
+	}{
+		{
+			name:       "compact",
+			wantStdout: `[{"owner":"@heaths","files":3,"bytes":0,"rules":0,"exclusive":0,"coOwned":0},{"owner":"@writers","files":2,"bytes":0,"rules":0,"exclusive":0,"coOwned":0}]`,
+		},
+		{
+			name: "jq",
+			tty:  true,
+			jq:   `.[] | select(.files > 2) | .owner`,
+			wantStdout: heredoc.Doc(`
+				@heaths
+			`),
+		},
+		{
+			name:     "template",
+			tty:      true,
+			template: `{{range .}}{{.owner}}: {{.files}}{{"\n"}}{{end}}`,
+			wantStdout: heredoc.Doc(`
+				@heaths: 3
+				@writers: 2
+			`),
+		},
+		{
+			name:     "template helpers",
+			template: `{{range .}}{{tablerow .owner .files}}{{end}}`,
+			wantStdout: heredoc.Doc(`
+				@heaths   3
+				@writers  2
+			`),
+		},
+		{
+			name:    "invalid jq",
+			jq:      `.[`,
+			wantErr: true,
+		},
+		{
+			name:     "invalid template",
+			template: `{{range}}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake(
+				console.WithStdoutTTY(tt.tty),
+				console.WithSize(80, 24),
+			)
+			opts := &GlobalOptions{
+				Console: fake,
+
+				colorDisabled: true,
+				jq:            tt.jq,
+				template:      tt.template,
+			}
+
+			err := printJson(opts, v)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			stdout, _, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
+
+func TestChangeType(t *testing.T) {
+	tests := []struct {
+		status    string
+		want      string
+		wantColor string
+	}{
+		{status: "ADDED", want: "A", wantColor: "\x1b[0;32mA\x1b[0m"},
+		{status: "CHANGED", want: "T", wantColor: "\x1b[0;35mT\x1b[0m"},
+		{status: "COPIED", want: "C", wantColor: "\x1b[0;34mC\x1b[0m"},
+		{status: "DELETED", want: "D", wantColor: "\x1b[0;31mD\x1b[0m"},
+		{status: "MODIFIED", want: "M", wantColor: "\x1b[0;33mM\x1b[0m"},
+		{status: "RENAMED", want: "R", wantColor: "\x1b[0;36mR\x1b[0m"},
+		{status: changeTypeUntracked, want: "?", wantColor: "\x1b[0;32m?\x1b[0m"},
+		{status: "", want: "?", wantColor: "?"},
+	}
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR", "")
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			opts := &GlobalOptions{
+				Console:       console.Fake(console.WithStdoutTTY(true)),
+				colorDisabled: true,
+			}
+			assert.Equal(t, tt.want, changeType(opts, tt.status))
+
+			opts.colorDisabled = false
+			assert.Equal(t, tt.wantColor, changeType(opts, tt.status))
+		})
+	}
+}
+
+func TestPrintOwnershipChanges(t *testing.T) {
+	fake := console.Fake()
+	opts := &GlobalOptions{
+		Console:       fake,
+		colorDisabled: true,
+	}
+
+	// Paths are aligned by characters instead of bytes.
+	printOwnershipChanges(opts, []codeowners.Change{
+		{Kind: codeowners.ChangeKindChanged, Path: "docs/café.md", OldOwners: []string{"@heaths"}, NewOwners: []string{"@writers"}},
+		{Kind: codeowners.ChangeKindGained, Path: "docs/index.md", NewOwners: []string{"@writers"}},
+		{Kind: codeowners.ChangeKindLost, Path: "ñandú.md", OldOwners: []string{"@heaths"}},
+	})
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, ""+
+		"  changed  docs/café.md   @heaths → @writers\n"+
+		"  gained   docs/index.md  @writers\n"+
+		"  lost     ñandú.md       @heaths\n", stdout.String())
+}
+
+func TestOwnerIndex(t *testing.T) {
+	index := newOwnerIndex(func(owner string) *ownerStats {
+		return &ownerStats{Owner: owner}
+	})
+
+	index.get("@Heaths").Files++
+	index.get("@writers").Files++
+	index.get("@heaths").Files++
+
+	require.Len(t, index.values, 2)
+	assert.Equal(t, ownerStats{Owner: "@Heaths", Files: 2}, *index.values[0])
+	assert.Equal(t, ownerStats{Owner: "@writers", Files: 1}, *index.values[1])
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/shurcooL/graphql"
+)
+
+const (
+	approvalApproved        = "APPROVED"
+	approvalReviewRequested = "REVIEW_REQUESTED"
+	approvalMissing         = "MISSING"
+	approvalUnknown         = "UNKNOWN"
+)
+
+// approvalRank orders approval statuses from worst to best.
+var approvalRank = map[string]int{
+	approvalMissing:         0,
+	approvalReviewRequested: 1,
+	approvalUnknown:         2,
+	approvalApproved:        3,
+}
+
+// reviews tracks who approved or was requested to review a pull request.
+type reviews struct {
+	client api.GQLClient
+
+	// Keys are lowercase logins or org/team slugs without a leading "@".
+	approvers map[string]bool
+	requested map[string]bool
+	teams     map[string]bool
+}
+
+// ownerApproval is the approval status of an owner of a file.
+type ownerApproval struct {
+	Owner    string `json:"owner"`
+	Approval string `json:"approval"`
+}
+
+func queryReviews(client api.GQLClient, repo repository.Repository, number int) (*reviews, error) {
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				// Only the latest approval or request for changes from each reviewer counts.
+				LatestOpinionatedReviews struct {
+					Nodes []struct {
+						State  string
+						Author struct {
+							Login string
+						}
+					}
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				} `graphql:"latestOpinionatedReviews(first: 100, after: $reviewsCursor)"`
+				ReviewRequests struct {
+					Nodes []struct {
+						RequestedReviewer struct {
+							User struct {
+								Login string
+							} `graphql:"... on User"`
+							Team struct {
+								CombinedSlug string
+							} `graphql:"... on Team"`
+						}
+					}
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				} `graphql:"reviewRequests(first: 100, after: $requestsCursor)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":          graphql.String(repo.Owner()),
+		"repo":           graphql.String(repo.Name()),
+		"number":         graphql.Int(number),
+		"reviewsCursor":  graphql.String(""),
+		"requestsCursor": graphql.String(""),
+	}
+
+	r := &reviews{
+		client:    client,
+		approvers: make(map[string]bool),
+		requested: make(map[string]bool),
+		teams:     make(map[string]bool),
+	}
+
+	// Both connections are paged together. Once one has no more pages, querying it again returns nodes already added.
+	for {
+		err := client.Query("PullRequestReviews", &query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		pr := query.Repository.PullRequest
+		for _, node := range pr.LatestOpinionatedReviews.Nodes {
+			if node.State == approvalApproved {
+				r.approvers[strings.ToLower(node.Author.Login)] = true
+			}
+		}
+
+		for _, node := range pr.ReviewRequests.Nodes {
+			reviewer := node.RequestedReviewer
+			if reviewer.User.Login != "" {
+				r.requested[strings.ToLower(reviewer.User.Login)] = true
+			} else if reviewer.Team.CombinedSlug != "" {
+				r.requested[strings.ToLower(reviewer.Team.CombinedSlug)] = true
+			}
+		}
+
+		if !pr.LatestOpinionatedReviews.PageInfo.HasNextPage && !pr.ReviewRequests.PageInfo.HasNextPage {
+			break
+		}
+
+		if pr.LatestOpinionatedReviews.PageInfo.HasNextPage {
+			variables["reviewsCursor"] = graphql.String(pr.LatestOpinionatedReviews.PageInfo.EndCursor)
+		}
+
+		if pr.ReviewRequests.PageInfo.HasNextPage {
+			variables["requestsCursor"] = graphql.String(pr.ReviewRequests.PageInfo.EndCursor)
+		}
+	}
+
+	return r, nil
+}
+
+// approval returns whether the owner approved, was requested to review, or is still missing.
+// Team owners have approved when any member of the team approved.
+// Owners specified by email address are unknown since their account cannot be determined.
+func (r *reviews) approval(owner string) (string, error) {
+	if !strings.HasPrefix(owner, "@") {
+		return approvalUnknown, nil
+	}
+
+	key := strings.ToLower(strings.TrimPrefix(owner, "@"))
+	if r.approvers[key] {
+		return approvalApproved, nil
+	}
+
+	if org, team, ok := strings.Cut(key, "/"); ok {
+		approved, err := r.teamApproved(org, team)
+		if err != nil {
+			return "", err
+		}
+		if approved {
+			return approvalApproved, nil
+		}
+	}
+
+	if r.requested[key] {
+		return approvalReviewRequested, nil
+	}
+
+	return approvalMissing, nil
+}
+
+// approvals returns the approval status of each owner.
+func (r *reviews) approvals(owners []string) ([]ownerApproval, error) {
+	approvals := make([]ownerApproval, 0, len(owners))
+	for _, owner := range owners {
+		approval, err := r.approval(owner)
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, ownerApproval{Owner: owner, Approval: approval})
+	}
+
+	return approvals, nil
+}
+
+// fileApproval returns the best approval status of any owner, since GitHub requires approval from only one owner of each file.
+// Unknown owners may have approved, so they are better than owners who were only requested to review.
+func fileApproval(approvals []ownerApproval) string {
+	if len(approvals) == 0 {
+		return ""
+	}
+
+	best := approvalMissing
+	for _, a := range approvals {
+		if approvalRank[a.Approval] > approvalRank[best] {
+			best = a.Approval
+		}
+	}
+
+	return best
+}
+
+func (r *reviews) teamApproved(org, team string) (bool, error) {
+	key := org + "/" + team
+	if approved, ok := r.teams[key]; ok {
+		return approved, nil
+	}
+
+	// Avoid querying team members when no one has approved.
+	if len(r.approvers) == 0 {
+		return false, nil
+	}
+
+	var query struct {
+		Organization struct {
+			Team struct {
+				Members struct {
+					Nodes []struct {
+						Login string
+					}
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				} `graphql:"members(first: 100, after: $endCursor)"`
+			} `graphql:"team(slug: $team)"`
+		} `graphql:"organization(login: $org)"`
+	}
+
+	variables := map[string]interface{}{
+		"org":       graphql.String(org),
+		"team":      graphql.String(team),
+		"endCursor": graphql.String(""),
+	}
+
+	approved := false
+	for !approved {
+		err := r.client.Query("TeamMembers", &query, variables)
+		if err != nil {
+			// Listing team members requires the read:org scope.
+			return false, fmt.Errorf("failed to get members of @%s; make sure you have the read:org scope: %w", key, err)
+		}
+
+		for _, node := range query.Organization.Team.Members.Nodes {
+			if r.approvers[strings.ToLower(node.Login)] {
+				approved = true
+				break
+			}
+		}
+
+		if query.Organization.Team.Members.PageInfo.HasNextPage {
+			variables["endCursor"] = graphql.String(query.Organization.Team.Members.PageInfo.EndCursor)
+		} else {
+			break
+		}
+	}
+
+	r.teams[key] = approved
+	return approved, nil
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	sortOwner     = "owner"
+	sortFiles     = "files"
+	sortBytes     = "bytes"
+	sortRules     = "rules"
+	sortExclusive = "exclusive"
+)
+
+func StatsCommand(globalOpts *GlobalOptions) *cobra.Command {
+	opts := &statsOptions{
+		GlobalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Shows how much of the repository each owner owns",
+		Long: "Shows the number of files and bytes each owner owns, how many rules name them, " +
+			"and how many of their files they own exclusively or with other owners.\n\n" +
+			"Statistics are shown as a table in a terminal or as JSON when piped to another program.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return stats(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Show statistics as JSON.")
+	opts.addExportFlags(cmd, func() bool { return opts.json })
+	StringEnumVarP(cmd, &opts.sort, "sort", "s", sortFiles, []string{sortOwner, sortFiles, sortBytes, sortRules, sortExclusive}, "Sort by")
+
+	return cmd
+}
+
+type statsOptions struct {
+	*GlobalOptions
+
+	json bool
+	sort string
+}
+
+type ownerStats struct {
+	Owner     string `json:"owner"`
+	Files     int    `json:"files"`
+	Bytes     int64  `json:"bytes"`
+	Rules     int    `json:"rules"`
+	Exclusive int    `json:"exclusive"`
+	CoOwned   int    `json:"coOwned"`
+}
+
+func stats(opts *statsOptions) (err error) {
+	c, err := opts.Codeowners()
+	if err != nil {
+		return
+	}
+
+	files, fileSize, err := statFiles(opts.GlobalOptions)
+	if err != nil {
+		return
+	}
+
+	index := newOwnerIndex(func(owner string) *ownerStats {
+		return &ownerStats{Owner: owner}
+	})
+
+	for _, rule := range c.File.Rules() {
+		seen := make(map[*ownerStats]bool)
+		for _, owner := range rule.OwnerNames() {
+			if s := index.get(owner); !seen[s] {
+				seen[s] = true
+				s.Rules++
+			}
+		}
+	}
+
+	for _, file := range files {
+		owners := c.Owners(file)
+		if len(owners) == 0 {
+			continue
+		}
+
+		size, err := fileSize(file)
+		if err != nil {
+			return err
+		}
+
+		for _, owner := range owners {
+			s := index.get(owner)
+			s.Files++
+			s.Bytes += size
+			if len(owners) == 1 {
+				s.Exclusive++
+			} else {
+				s.CoOwned++
+			}
+		}
+	}
+
+	results := make([]ownerStats, 0, len(index.values))
+	for _, s := range index.values {
+		results = append(results, *s)
+	}
+	sortStats(results, opts.sort)
+
+	if opts.json || opts.isExporting() || !opts.Console.IsStdoutTTY() {
+		return printJson(opts.GlobalOptions, results)
+	}
+
+	tp := newTablePrinter(opts.GlobalOptions)
+	for _, header := range []string{"OWNER", "FILES", "BYTES", "RULES", "EXCLUSIVE", "CO-OWNED"} {
+		tp.AddField(header)
+	}
+	tp.EndRow()
+
+	for _, s := range results {
+		tp.AddField(s.Owner)
+		tp.AddField(strconv.Itoa(s.Files))
+		tp.AddField(strconv.FormatInt(s.Bytes, 10))
+		tp.AddField(strconv.Itoa(s.Rules))
+		tp.AddField(strconv.Itoa(s.Exclusive))
+		tp.AddField(strconv.Itoa(s.CoOwned))
+		tp.EndRow()
+	}
+
+	return tp.Render()
+}
+
+// statFiles returns the files in the repository and a function that returns the size of each.
+// Files in a remote repository are listed with their sizes instead of querying each file.
+func statFiles(opts *GlobalOptions) ([]string, func(string) (int64, error), error) {
+	if opts.Remote && opts.fs == nil {
+		ref, err := opts.RefName()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		files, err := opts.remoteFiles(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sizes := make(map[string]int64, len(files))
+		for _, f := range files {
+			sizes[f.Path] = f.Size
+		}
+
+		return filePaths(files), func(file string) (int64, error) {
+			return sizes[file], nil
+		}, nil
+	}
+
+	root, err := opts.RootFS()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files, err := opts.Files()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return files, func(file string) (int64, error) {
+		return fileSize(root, file)
+	}, nil
+}
+
+func fileSize(root fs.FS, file string) (int64, error) {
+	stat, err := fs.Stat(root, file)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Tracked files may be deleted from the working tree.
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return stat.Size(), nil
+}
+
+func sortStats(results []ownerStats, by string) {
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+
+		var x, y int64
+		switch by {
+		case sortFiles:
+			x, y = int64(a.Files), int64(b.Files)
+		case sortBytes:
+			x, y = a.Bytes, b.Bytes
+		case sortRules:
+			x, y = int64(a.Rules), int64(b.Rules)
+		case sortExclusive:
+			x, y = int64(a.Exclusive), int64(b.Exclusive)
+		}
+
+		// Sort numbers descending and owners ascending.
+		if x != y {
+			return x > y
+		}
+		return strings.ToLower(a.Owner) < strings.ToLower(b.Owner)
+	})
+}
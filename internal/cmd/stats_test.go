@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/heaths/go-console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestStats(t *testing.T) {
+	content := heredoc.Doc(`
+		* @heaths
+		docs/ @writers @Heaths
+		*.md @writers
+		old/ @writers
+	`)
+	fs := fstest.MapFS{
+		"CODEOWNERS":      {Data: []byte(content)},
+		"main.go":         {Data: []byte("package main")},
+		"README.md":       {Data: []byte("# README")},
+		"docs/guide.html": {Data: []byte("<html></html>")},
+	}
+
+	tests := []struct {
+		name       string
+		tty        bool
+		sort       string
+		wantStdout string
+	}{
+		{
+			name: "table",
+			tty:  true,
+			sort: sortFiles,
+			wantStdout: heredoc.Doc(`
+				OWNER     FILES  BYTES  RULES  EXCLUSIVE  CO-OWNED
+				@heaths   3      86     2      2          1
+				@writers  2      21     3      1          1
+			`),
+		},
+		{
+			name:       "json",
+			sort:       sortRules,
+			wantStdout: `[{"owner":"@writers","files":2,"bytes":21,"rules":3,"exclusive":1,"coOwned":1},{"owner":"@heaths","files":3,"bytes":86,"rules":2,"exclusive":2,"coOwned":1}]`,
+		},
+		{
+			name:       "sort by owner",
+			sort:       sortOwner,
+			wantStdout: `[{"owner":"@heaths","files":3,"bytes":86,"rules":2,"exclusive":2,"coOwned":1},{"owner":"@writers","files":2,"bytes":21,"rules":3,"exclusive":1,"coOwned":1}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake(
+				console.WithStdoutTTY(tt.tty),
+				console.WithSize(80, 24),
+			)
+
+			opts := statsOptions{
+				GlobalOptions: &GlobalOptions{
+					Console: fake,
+
+					colorDisabled: true,
+					fs:            fs,
+				},
+				sort: tt.sort,
+			}
+
+			err := stats(&opts)
+			require.NoError(t, err)
+
+			stdout, _, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
+
+func TestStats_remote(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`DefaultBranch`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"defaultBranchRef":{"name":"main"}}}}`)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"expression":"main:CODEOWNERS"`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"object":{"__typename":"Blob","byteSize":25,"text":"* @heaths\ndocs/ @writers\n"}}}}`)
+
+	// Sizes are listed with files instead of querying each file.
+	gock.New("https://api.github.com").
+		Get("/repos/heaths/gh-codeowners/git/trees/main").
+		MatchParam("recursive", "1").
+		Reply(200).
+		JSON(`{
+			"tree": [
+				{"path": "CODEOWNERS", "type": "blob", "size": 25},
+				{"path": "docs", "type": "tree"},
+				{"path": "docs/index.md", "type": "blob", "size": 8},
+				{"path": "main.go", "type": "blob", "size": 12}
+			],
+			"truncated": false
+		}`)
+
+	fake := console.Fake()
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := statsOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+			Remote:  true,
+			Repo:    repo,
+
+			host:          "github.com",
+			authToken:     "***",
+			colorDisabled: true,
+		},
+		sort: sortOwner,
+	}
+
+	err = stats(&opts)
+	require.NoError(t, err)
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, `[{"owner":"@heaths","files":2,"bytes":37,"rules":1,"exclusive":2,"coOwned":0},{"owner":"@writers","files":1,"bytes":8,"rules":1,"exclusive":1,"coOwned":0}]`, stdout.String())
+	assert.True(t, gock.IsDone())
+}
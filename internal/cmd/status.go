@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/heaths/gh-codeowners/internal/git"
+	"github.com/spf13/cobra"
+)
+
+const (
+	changeTypeUntracked = "UNTRACKED"
+)
+
+func StatusCommand(globalOpts *GlobalOptions) *cobra.Command {
+	opts := &statusOptions{
+		GlobalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows the owners of uncommitted changes",
+		Long: "Shows the owners of staged and unstaged changes in the working tree using your CODEOWNERS file, " +
+			"so you know who will own what you are changing before you open a pull request.\n\n" +
+			"Files are grouped by owner in a terminal or shown as JSON when piped to another program.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return status(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Show files as JSON.")
+	opts.addExportFlags(cmd, func() bool { return opts.json })
+
+	return cmd
+}
+
+type statusOptions struct {
+	*GlobalOptions
+
+	json bool
+}
+
+type changes struct {
+	Staged   []file `json:"staged"`
+	Unstaged []file `json:"unstaged"`
+}
+
+func status(opts *statusOptions) (err error) {
+	c, err := opts.Codeowners()
+	if err != nil {
+		return
+	}
+
+	root, err := opts.RootDir()
+	if err != nil {
+		return
+	}
+
+	statuses, err := git.Status(root)
+	if err != nil {
+		return
+	}
+
+	result := changes{
+		Staged:   []file{},
+		Unstaged: []file{},
+	}
+
+	for _, s := range statuses {
+		owners := c.Owners(s.Path)
+		if s.Staged == '?' {
+			result.Unstaged = append(result.Unstaged, file{
+				Path:       s.Path,
+				ChangeType: changeTypeUntracked,
+				Owners:     owners,
+			})
+			continue
+		}
+
+		if s.Staged != ' ' {
+			result.Staged = append(result.Staged, file{
+				Path:       s.Path,
+				ChangeType: statusChangeType(s.Staged),
+				Owners:     owners,
+			})
+		}
+
+		if s.Unstaged != ' ' {
+			result.Unstaged = append(result.Unstaged, file{
+				Path:       s.Path,
+				ChangeType: statusChangeType(s.Unstaged),
+				Owners:     owners,
+			})
+		}
+	}
+
+	if opts.json || opts.isExporting() || !opts.Console.IsStdoutTTY() {
+		return printJson(opts.GlobalOptions, result)
+	}
+
+	w := opts.Console.Stdout()
+	if len(result.Staged) == 0 && len(result.Unstaged) == 0 {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+
+	printSection := func(name string, files []file) {
+		if len(files) == 0 {
+			return
+		}
+
+		groups, unowned := groupByOwner(files)
+		fmt.Fprintf(w, "%s: %s owned by %s\n", name, plural(len(files), "file"), plural(len(groups), "owner"))
+		printOwnerGroups(opts.GlobalOptions, groups, unowned, nil)
+	}
+
+	printSection("Staged changes", result.Staged)
+	if len(result.Staged) > 0 && len(result.Unstaged) > 0 {
+		fmt.Fprintln(w)
+	}
+	printSection("Unstaged changes", result.Unstaged)
+
+	return
+}
+
+// statusChangeType converts a git status code to a GitHub PatchStatus.
+func statusChangeType(code byte) string {
+	switch code {
+	case 'A':
+		return "ADDED"
+	case 'C':
+		return "COPIED"
+	case 'D':
+		return "DELETED"
+	case 'M':
+		return "MODIFIED"
+	case 'R':
+		return "RENAMED"
+	default:
+		return "CHANGED"
+	}
+}
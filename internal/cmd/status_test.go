@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/heaths/gh-codeowners/internal/gittest"
+	"github.com/heaths/go-console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	root := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": "* @heaths\ndocs/ @writers\n",
+		"main.go":    "package main\n",
+		"docs/a.md":  "# A\n",
+	})
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs", "a.md"), []byte("# A\n\nStaged.\n"), 0644))
+	gittest.Run(t, root, "add", "docs/a.md")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs", "a.md"), []byte("# A\n\nUnstaged.\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "new.go"), []byte("package main\n"), 0644))
+
+	tests := []struct {
+		name       string
+		tty        bool
+		json       bool
+		wantStdout string
+	}{
+		{
+			name: "tty",
+			tty:  true,
+			wantStdout: heredoc.Doc(`
+				Staged changes: 1 file owned by 1 owner
+
+				@writers (1 file)
+				  M  docs/a.md
+
+				Unstaged changes: 3 files owned by 2 owners
+
+				@heaths (2 files)
+				  M  main.go
+				  ?  new.go
+
+				@writers (1 file)
+				  M  docs/a.md
+			`),
+		},
+		{
+			name: "json",
+			json: true,
+			wantStdout: `{"staged":[{"path":"docs/a.md","changeType":"MODIFIED","owners":["@writers"]}],` +
+				`"unstaged":[{"path":"docs/a.md","changeType":"MODIFIED","owners":["@writers"]},` +
+				`{"path":"main.go","changeType":"MODIFIED","owners":["@heaths"]},` +
+				`{"path":"new.go","changeType":"UNTRACKED","owners":["@heaths"]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake(console.WithStdoutTTY(tt.tty))
+			opts := statusOptions{
+				GlobalOptions: &GlobalOptions{
+					Console: fake,
+
+					colorDisabled: true,
+					root:          root,
+				},
+				json: tt.json,
+			}
+
+			err := status(&opts)
+			require.NoError(t, err)
+
+			stdout, _, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
@@ -1,10 +1,7 @@
 package cmd
 
 import (
-	"github.com/cli/go-gh"
-	"github.com/cli/go-gh/pkg/api"
 	"github.com/heaths/gh-codeowners/internal/codeowners"
-	"github.com/heaths/gh-codeowners/internal/git"
 	"github.com/spf13/cobra"
 )
 
@@ -18,12 +15,7 @@ func ViewCommand(globalOpts *GlobalOptions) *cobra.Command {
 		Short: "Views the CODEOWNERS file with errors highlighted",
 		Long:  "Checks your CODEOWNERS files for errors as determined by GitHub and renders the CODEOWNERS file.",
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			err = opts.EnsureRepository()
-			if err != nil {
-				return
-			}
-
-			err = opts.IsAuthenticated()
+			err = opts.ensure(opts.GlobalOptions)
 			if err != nil {
 				return
 			}
@@ -32,39 +24,31 @@ func ViewCommand(globalOpts *GlobalOptions) *cobra.Command {
 		},
 	}
 
+	opts.addFlags(cmd)
+	opts.addRefFlag(cmd)
+
 	return cmd
 }
 
 type viewOptions struct {
 	*GlobalOptions
+	checkOptions
 }
 
 func view(opts *viewOptions) (err error) {
-	clientOpts := &api.ClientOptions{
-		Host:      opts.host,
-		AuthToken: opts.authToken,
-	}
-	client, err := gh.GQLClient(clientOpts)
-	if err != nil {
-		return
-	}
-
-	refName, err := git.RefName()
-	if err != nil {
-		return
-	}
-
-	errors, err := codeowners.QueryErrors(client, opts.Repo, refName)
+	errors, err := findErrors(opts.GlobalOptions, &opts.checkOptions)
 	if err != nil {
 		return
 	}
 
 	renderOpts := codeowners.RenderOptions{
 		Console: opts.Console,
+		Dialect: opts.Dialect,
 		Color:   opts.Color,
 	}
 
-	root, err := opts.RootFS()
+	// Render the same CODEOWNERS file errors were found in.
+	root, err := opts.checkOptions.rootFS(opts.GlobalOptions)
 	if err != nil {
 		return
 	}
@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"io/fs"
+	"os"
+	"path/filepath"
 	"testing"
 	"testing/fstest"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/cli/go-gh/pkg/repository"
+	"github.com/heaths/gh-codeowners/internal/git"
+	"github.com/heaths/gh-codeowners/internal/gittest"
 	"github.com/heaths/go-console"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,6 +26,8 @@ func TestView(t *testing.T) {
 	tests := []struct {
 		name       string
 		tty        bool
+		offline    bool
+		deadRules  bool
 		fs         fs.FS
 		mocks      func()
 		wantStdout string
@@ -122,6 +128,35 @@ func TestView(t *testing.T) {
 				docs/ %[1]s[0;38;2;255;0;0m@writers%[1]s[0m
 			`, "\033"),
 		},
+		{
+			name:    "offline (tty)",
+			tty:     true,
+			offline: true,
+			fs: fstest.MapFS{
+				"CODEOWNERS": {Data: []byte(content + "!*.md @heaths\n")},
+			},
+			wantStdout: heredoc.Docf(`
+				%[1]s[0;38;2;0;255;0m# comment%[1]s[0m
+				* @heaths
+				docs/ @writers
+				%[1]s[0;38;2;255;0;0m!*.md%[1]s[0m @heaths
+			`, "\033"),
+		},
+		{
+			name:      "dead rules (tty)",
+			tty:       true,
+			offline:   true,
+			deadRules: true,
+			fs: fstest.MapFS{
+				"CODEOWNERS": {Data: []byte(content)},
+				"main.go":    {Data: []byte("package main")},
+			},
+			wantStdout: heredoc.Docf(`
+				%[1]s[0;38;2;0;255;0m# comment%[1]s[0m
+				* @heaths
+				%[1]s[0;38;2;255;0;0mdocs/%[1]s[0m @writers
+			`, "\033"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -145,6 +180,10 @@ func TestView(t *testing.T) {
 					authToken: "***",
 					fs:        tt.fs,
 				},
+				checkOptions: checkOptions{
+					offline:   tt.offline,
+					deadRules: tt.deadRules,
+				},
 			}
 
 			if tt.mocks != nil {
@@ -159,3 +198,157 @@ func TestView(t *testing.T) {
 		})
 	}
 }
+
+func TestView_ref(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	if _, _, err := git.Exec("--version"); err != nil {
+		t.Skip(err)
+	}
+
+	content := heredoc.Doc(`
+		* @heaths
+		docs/ @writers
+	`)
+
+	root := t.TempDir()
+	path := filepath.Join(root, "CODEOWNERS")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "--all"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--message", "Initial commit"},
+		{"tag", "v1.0"},
+	} {
+		_, _, err := git.Exec(append([]string{"-C", root}, args...)...)
+		require.NoError(t, err)
+	}
+
+	// Errors are reported for the ref, so the working tree must not be rendered.
+	require.NoError(t, os.WriteFile(path, []byte("# changed\n"+content), 0644))
+
+	sha, err := git.ResolveRef(root, "v1.0")
+	require.NoError(t, err)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"ref":"` + sha + `"`).
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"codeowners": {
+						"errors": [
+							{
+								"path": "CODEOWNERS",
+								"kind": "Unknown owner",
+								"line": 2,
+								"column": 7,
+								"source": "docs/ @writers"
+							}
+						]
+					}
+				}
+			}
+		}`)
+
+	fake := console.Fake(console.WithStdoutTTY(true))
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := viewOptions{
+		GlobalOptions: &GlobalOptions{
+			Color: ColorOptions{
+				Comment: "#00FF00",
+				Error:   "#FF0000",
+			},
+			Console: fake,
+			Ref:     "v1.0",
+			Repo:    repo,
+
+			host:      "github.com",
+			authToken: "***",
+			root:      root,
+		},
+	}
+
+	err = view(&opts)
+	require.NoError(t, err)
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, heredoc.Docf(`
+		* @heaths
+		docs/ %[1]s[0;38;2;255;0;0m@writers%[1]s[0m
+	`, "\033"), stdout.String())
+	assert.True(t, gock.IsDone())
+}
+
+func TestView_queriedRef(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	content := heredoc.Doc(`
+		* @heaths
+		docs/ @writers
+	`)
+
+	root := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": content,
+	})
+
+	head, err := git.ResolveRef(root, "HEAD")
+	require.NoError(t, err)
+
+	// Errors are reported for the committed CODEOWNERS file, so uncommitted changes must not be rendered.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("# changed\n"+content), 0644))
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"ref":"` + head + `"`).
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"codeowners": {
+						"errors": [
+							{
+								"path": "CODEOWNERS",
+								"kind": "Unknown owner",
+								"line": 2,
+								"column": 7,
+								"source": "docs/ @writers"
+							}
+						]
+					}
+				}
+			}
+		}`)
+
+	fake := console.Fake(console.WithStdoutTTY(true))
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	opts := viewOptions{
+		GlobalOptions: &GlobalOptions{
+			Color: ColorOptions{
+				Comment: "#00FF00",
+				Error:   "#FF0000",
+			},
+			Console: fake,
+			Repo:    repo,
+
+			host:      "github.com",
+			authToken: "***",
+			root:      root,
+		},
+	}
+
+	err = view(&opts)
+	require.NoError(t, err)
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, heredoc.Docf(`
+		* @heaths
+		docs/ %[1]s[0;38;2;255;0;0m@writers%[1]s[0m
+	`, "\033"), stdout.String())
+	assert.True(t, gock.IsDone())
+}
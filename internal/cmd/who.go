@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/spf13/cobra"
+)
+
+func WhoCommand(globalOpts *GlobalOptions) *cobra.Command {
+	opts := &whoOptions{
+		GlobalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "who <path>...",
+		Short: "Explains which rules own files",
+		Long: "Shows the rule that determines the owners for each path, and any earlier matching rules it overrides. " +
+			"Paths are relative to the current directory like other git commands, or to the repository root with --repo.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			opts.paths = args
+			return who(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Show ownership as JSON.")
+	opts.addExportFlags(cmd, func() bool { return opts.json })
+
+	return cmd
+}
+
+type whoOptions struct {
+	*GlobalOptions
+
+	json  bool
+	paths []string
+}
+
+func who(opts *whoOptions) (err error) {
+	c, err := opts.Codeowners()
+	if err != nil {
+		return
+	}
+
+	results := make([]ownership, 0, len(opts.paths))
+	for _, p := range opts.paths {
+		p, err = opts.RepoPath(p)
+		if err != nil {
+			return
+		}
+
+		result := ownership{
+			Path: p,
+		}
+
+		// GitLab combines owners from the winning rule in each section.
+		winners := c.Winners(p)
+		if len(winners) > 0 {
+			result.Owners = c.Owners(p)
+			result.Rule = newRule(winners[len(winners)-1])
+
+			for _, winner := range winners[:len(winners)-1] {
+				result.Combined = append(result.Combined, *newRule(winner))
+			}
+
+			for _, match := range c.Matches(p) {
+				if !containsRule(winners, match) {
+					result.Overridden = append(result.Overridden, *newRule(match))
+				}
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	if opts.json || opts.isExporting() {
+		return printJson(opts.GlobalOptions, results)
+	}
+
+	w := opts.Console.Stdout()
+	for i, result := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintln(w, result.Path)
+		if result.Rule == nil {
+			fmt.Fprintln(w, indent+"no matching rule")
+			continue
+		}
+
+		for _, rule := range result.Combined {
+			fmt.Fprintf(w, "%smatched by line %d: %s\n", indent, rule.Line, rule)
+		}
+		fmt.Fprintf(w, "%smatched by line %d: %s\n", indent, result.Rule.Line, result.Rule)
+		for _, rule := range result.Overridden {
+			fmt.Fprintf(w, "%soverrides line %d: %s\n", indent, rule.Line, rule)
+		}
+	}
+
+	return
+}
+
+type ownership struct {
+	Path       string   `json:"path"`
+	Owners     []string `json:"owners"`
+	Rule       *rule    `json:"rule"`
+	Combined   []rule   `json:"combined,omitempty"`
+	Overridden []rule   `json:"overridden"`
+}
+
+type rule struct {
+	Line    int      `json:"line"`
+	Section string   `json:"section,omitempty"`
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+func newRule(r *codeowners.Rule) *rule {
+	section := ""
+	if r.Section != nil {
+		section = r.Section.Name
+	}
+
+	return &rule{
+		Line:    r.Line,
+		Section: section,
+		Pattern: r.Pattern.Text,
+		Owners:  r.EffectiveOwners(),
+	}
+}
+
+func (r rule) String() string {
+	s := strings.Join(append([]string{r.Pattern}, r.Owners...), " ")
+	if r.Section != "" {
+		s = fmt.Sprintf("[%s] %s", r.Section, s)
+	}
+	return s
+}
+
+func containsRule(rules []*codeowners.Rule, r *codeowners.Rule) bool {
+	for _, rule := range rules {
+		if rule == r {
+			return true
+		}
+	}
+	return false
+}
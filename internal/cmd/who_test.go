@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/heaths/gh-codeowners/internal/gittest"
+	"github.com/heaths/go-console"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWho(t *testing.T) {
+	content := heredoc.Doc(`
+		# comment
+		* @heaths
+		*.md @writers
+		docs/ @writers @editors # Documentation
+	`)
+	tests := []struct {
+		name       string
+		json       bool
+		paths      []string
+		wantStdout string
+	}{
+		{
+			name:  "text",
+			paths: []string{"main.go", "./docs/README.md"},
+			wantStdout: heredoc.Doc(`
+				main.go
+				  matched by line 2: * @heaths
+
+				docs/README.md
+				  matched by line 4: docs/ @writers @editors
+				  overrides line 2: * @heaths
+				  overrides line 3: *.md @writers
+			`),
+		},
+		{
+			name:       "json without overrides",
+			paths:      []string{"main.go"},
+			json:       true,
+			wantStdout: `[{"path":"main.go","owners":["@heaths"],"rule":{"line":2,"pattern":"*","owners":["@heaths"]},"overridden":null}]`,
+		},
+		{
+			name:  "json",
+			paths: []string{"docs/README.md"},
+			json:  true,
+			wantStdout: `[{"path":"docs/README.md","owners":["@writers","@editors"],"rule":{"line":4,"pattern":"docs/","owners":["@writers","@editors"]},` +
+				`"overridden":[{"line":2,"pattern":"*","owners":["@heaths"]},{"line":3,"pattern":"*.md","owners":["@writers"]}]}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := console.Fake()
+			opts := whoOptions{
+				GlobalOptions: &GlobalOptions{
+					Console: fake,
+
+					colorDisabled: true,
+					fs: fstest.MapFS{
+						"CODEOWNERS": {Data: []byte(content)},
+					},
+				},
+				json:  tt.json,
+				paths: tt.paths,
+			}
+
+			err := who(&opts)
+			require.NoError(t, err)
+
+			stdout, _, _ := fake.Buffers()
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
+
+func TestWho_noMatch(t *testing.T) {
+	fake := console.Fake()
+	opts := whoOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+			fs: fstest.MapFS{
+				"CODEOWNERS": {Data: []byte("docs/ @writers\n")},
+			},
+		},
+		paths: []string{"main.go"},
+	}
+
+	err := who(&opts)
+	require.NoError(t, err)
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, "main.go\n  no matching rule\n", stdout.String())
+}
+
+func TestWho_cwd(t *testing.T) {
+	root := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS":     "* @heaths\ndocs/ @writers\n",
+		"main.go":        "package main\n",
+		"docs/README.md": "# README\n",
+	})
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(filepath.Join(root, "docs")))
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+
+	fake := console.Fake()
+	opts := whoOptions{
+		GlobalOptions: &GlobalOptions{
+			Console: fake,
+
+			colorDisabled: true,
+			root:          root,
+		},
+		paths: []string{"README.md", "../main.go"},
+	}
+
+	err = who(&opts)
+	require.NoError(t, err)
+
+	stdout, _, _ := fake.Buffers()
+	assert.Equal(t, heredoc.Doc(`
+		docs/README.md
+		  matched by line 2: docs/ @writers
+		  overrides line 1: * @heaths
+
+		main.go
+		  matched by line 1: * @heaths
+	`), stdout.String())
+
+	opts.paths = []string{"../../outside.go"}
+	err = who(&opts)
+	assert.ErrorContains(t, err, "outside repository")
+}
@@ -0,0 +1,105 @@
+package codeowners
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Unmatched finds rules with patterns that do not match any of the files.
+func (c Codeowners) Unmatched(files []string) Errors {
+	var errors Errors
+	for _, r := range c.rules {
+		matched := false
+		for _, file := range files {
+			if r.pattern.Match(file) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			detail := fmt.Sprintf("%s does not match any files in the repository", r.rule.Pattern.Text)
+			errors = append(errors, c.newRuleError(ErrorKindNoMatchingFiles, r.rule, detail))
+		}
+	}
+
+	return errors
+}
+
+// Shadowed finds rules where every file they match is also matched by later rules in the same section,
+// so their owners never apply.
+func (c Codeowners) Shadowed(files []string) Errors {
+	matched := make([]bool, len(c.rules))
+	effective := make([]bool, len(c.rules))
+	shadowers := make([]map[int]bool, len(c.rules))
+
+	var matches []int
+	for _, file := range files {
+		matches = matches[:0]
+		for i, r := range c.rules {
+			if r.pattern.Match(file) {
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		// The last matching rule in each section wins.
+		winners := make(map[string]int)
+		for _, i := range matches {
+			winners[c.rules[i].section] = i
+		}
+
+		for _, i := range matches {
+			winner := winners[c.rules[i].section]
+			if i == winner {
+				effective[i] = true
+				continue
+			}
+
+			matched[i] = true
+			if shadowers[i] == nil {
+				shadowers[i] = make(map[int]bool)
+			}
+			shadowers[i][c.rules[winner].rule.Line] = true
+		}
+	}
+
+	var errors Errors
+	for i, r := range c.rules {
+		if !matched[i] || effective[i] {
+			continue
+		}
+
+		lines := make([]int, 0, len(shadowers[i]))
+		for line := range shadowers[i] {
+			lines = append(lines, line)
+		}
+		sort.Ints(lines)
+
+		detail := fmt.Sprintf("every file matched by %s is also matched by line(s) %s", r.rule.Pattern.Text, joinInts(lines, ", "))
+		errors = append(errors, c.newRuleError(ErrorKindShadowedRule, r.rule, detail))
+	}
+
+	return errors
+}
+
+func joinInts(values []int, sep string) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, sep)
+}
+
+func (c Codeowners) newRuleError(kind ErrorKind, rule *Rule, detail string) Error {
+	source := ""
+	if line := c.File.Line(rule.Line); line != nil {
+		source = line.Text
+	}
+
+	return newError(kind, c.File.Path, rule.Line, rule.Pattern.Column, source, detail)
+}
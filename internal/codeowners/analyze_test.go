@@ -0,0 +1,81 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeowners_Unmatched(t *testing.T) {
+	var source = heredoc.Doc(`
+		* @heaths
+		  /old/ @writers
+		docs/ @writers
+		*.rs @rustaceans # No Rust yet
+	`)
+
+	f, err := Parse(strings.NewReader(source))
+	require.NoError(t, err)
+	f.Path = "CODEOWNERS"
+
+	c, err := New(f)
+	require.NoError(t, err)
+
+	errors := c.Unmatched([]string{"main.go", "docs/README.md"})
+	require.Len(t, errors, 2)
+
+	assert.Equal(t, ErrorKindNoMatchingFiles, errors[0].Kind)
+	assert.Equal(t, "CODEOWNERS", errors[0].Path)
+	assert.Equal(t, 2, errors[0].Line)
+	assert.Equal(t, 3, errors[0].Column)
+	assert.Equal(t, "/old/", errors[0].Token())
+	assert.Equal(t, heredoc.Doc(`
+		No matching files on line 2: /old/ does not match any files in the repository
+
+		    /old/ @writers
+		    ^`), errors[0].Message)
+
+	assert.Equal(t, 4, errors[1].Line)
+	assert.Equal(t, "*.rs", errors[1].Token())
+}
+
+func TestCodeowners_Shadowed(t *testing.T) {
+	var source = heredoc.Doc(`
+		* @heaths
+		docs/ @writers
+		*.md @editors
+		docs/api/ @api
+		src/ @developers
+		src/*.go @gophers
+	`)
+
+	f, err := Parse(strings.NewReader(source))
+	require.NoError(t, err)
+	f.Path = "CODEOWNERS"
+
+	c, err := New(f)
+	require.NoError(t, err)
+
+	files := []string{
+		"main.go",
+		"docs/README.md",
+		"docs/api/index.html",
+		"src/main.go",
+		"src/lib/lib.go",
+	}
+
+	errors := c.Shadowed(files)
+	require.Len(t, errors, 1)
+
+	assert.Equal(t, ErrorKindShadowedRule, errors[0].Kind)
+	assert.Equal(t, 2, errors[0].Line)
+	assert.Equal(t, 1, errors[0].Column)
+	assert.Equal(t, heredoc.Doc(`
+		Shadowed rule on line 2: every file matched by docs/ is also matched by line(s) 3, 4
+
+		  docs/ @writers
+		  ^`), errors[0].Message)
+}
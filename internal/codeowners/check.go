@@ -0,0 +1,88 @@
+package codeowners
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	userRE  = regexp.MustCompile(`^@[A-Za-z0-9][A-Za-z0-9_-]*$`)
+	teamRE  = regexp.MustCompile(`^@[A-Za-z0-9][A-Za-z0-9_-]*/[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	emailRE = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+	// GitLab supports nested groups and roles e.g., @@maintainer.
+	gitlabRE = regexp.MustCompile(`^@@?[A-Za-z0-9_.-]+(?:/[A-Za-z0-9_.-]+)*$`)
+)
+
+// Check finds syntax in a CODEOWNERS file that GitHub rejects or ignores without querying GitHub.
+// Unlike QueryErrors, Check cannot determine whether owners exist or have write access.
+func Check(f *File) Errors {
+	var errors Errors
+	for _, line := range f.Lines {
+		newRuleError := func(kind ErrorKind, column int, detail string) {
+			errors = append(errors, newError(kind, f.Path, line.Number, column, line.Text, detail))
+		}
+
+		checkOwners := func(owners []Token) {
+			for _, owner := range owners {
+				if !isValidOwner(owner.Text, f.Dialect) {
+					newRuleError(ErrorKindInvalidOwner, owner.Column, "owner must be a @username, @org/team-name, or email address")
+				}
+			}
+		}
+
+		if line.Section != nil {
+			checkOwners(line.Section.Owners)
+			continue
+		}
+
+		rule := line.Rule
+		if rule == nil {
+			continue
+		}
+
+		pattern := rule.Pattern
+		if strings.HasPrefix(pattern.Text, "!") {
+			newRuleError(ErrorKindInvalidPattern, pattern.Column, "negating a pattern using ! is not supported")
+		} else if strings.HasPrefix(pattern.Text, `\#`) {
+			newRuleError(ErrorKindInvalidPattern, pattern.Column, "escaping a pattern starting with # is not supported")
+		}
+
+		if idx := indexCharacterRange(pattern.Text); idx >= 0 {
+			newRuleError(ErrorKindInvalidPattern, pattern.Column+idx, "character ranges using [ ] are not supported")
+		}
+
+		if len(rule.EffectiveOwners()) == 0 {
+			newRuleError(ErrorKindMissingOwners, pattern.Column, "pattern has no owners so matching files have no owners")
+			continue
+		}
+
+		checkOwners(rule.Owners)
+	}
+
+	return errors
+}
+
+func indexCharacterRange(pattern string) int {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			if strings.IndexByte(pattern[i+1:], ']') >= 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isValidOwner(owner string, dialect Dialect) bool {
+	if dialect == DialectGitLab && gitlabRE.MatchString(owner) {
+		return true
+	}
+
+	return userRE.MatchString(owner) ||
+		teamRE.MatchString(owner) ||
+		emailRE.MatchString(owner)
+}
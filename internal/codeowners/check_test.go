@@ -0,0 +1,134 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	type result struct {
+		Kind   ErrorKind
+		Line   int
+		Column int
+		Token  string
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   []result
+	}{
+		{
+			name: "valid",
+			source: heredoc.Doc(`
+				# comment
+				* @heaths # default
+				docs/ @org/writers docs@example.com
+				path\ with\ spaces/ @user_shortcode
+			`),
+		},
+		{
+			name:   "negation",
+			source: "!*.md @heaths\n",
+			want: []result{
+				{Kind: ErrorKindInvalidPattern, Line: 1, Column: 1, Token: "!*.md"},
+			},
+		},
+		{
+			name:   "character range",
+			source: "* @heaths\n*.[ch] @heaths\n",
+			want: []result{
+				{Kind: ErrorKindInvalidPattern, Line: 2, Column: 3, Token: "[ch]"},
+			},
+		},
+		{
+			name:   "escaped character range",
+			source: `\[ch] @heaths`,
+		},
+		{
+			name:   "escaped comment",
+			source: `\#foo @heaths`,
+			want: []result{
+				{Kind: ErrorKindInvalidPattern, Line: 1, Column: 1, Token: `\#foo`},
+			},
+		},
+		{
+			name:   "invalid owners",
+			source: "* heaths @org/ @-foo @heaths\n",
+			want: []result{
+				{Kind: ErrorKindInvalidOwner, Line: 1, Column: 3, Token: "heaths"},
+				{Kind: ErrorKindInvalidOwner, Line: 1, Column: 10, Token: "@org/"},
+				{Kind: ErrorKindInvalidOwner, Line: 1, Column: 16, Token: "@-foo"},
+			},
+		},
+		{
+			name:   "missing owners",
+			source: "* @heaths\n  docs/ # no owners\n",
+			want: []result{
+				{Kind: ErrorKindMissingOwners, Line: 2, Column: 3, Token: "docs/"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(strings.NewReader(tt.source))
+			require.NoError(t, err)
+			f.Path = "CODEOWNERS"
+
+			var got []result
+			for _, e := range Check(f) {
+				assert.Equal(t, "CODEOWNERS", e.Path)
+				assert.Equal(t, f.Line(e.Line).Text, e.Source)
+				got = append(got, result{
+					Kind:   e.Kind,
+					Line:   e.Line,
+					Column: e.Column,
+					Token:  e.Token(),
+				})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheck_message(t *testing.T) {
+	f, err := Parse(strings.NewReader("* @heaths\n*.md heaths\n"))
+	require.NoError(t, err)
+
+	errors := Check(f)
+	require.Len(t, errors, 1)
+	assert.Equal(t, heredoc.Doc(`
+		Invalid owner on line 2: owner must be a @username, @org/team-name, or email address
+
+		  *.md heaths
+		       ^`), errors[0].Message)
+}
+
+func TestCheck_gitLab(t *testing.T) {
+	var source = heredoc.Doc(`
+		[Documentation] @group/subgroup/docs
+		docs/
+		[Development] @@developer invalid
+		*.go
+		[Empty]
+		*.txt
+	`)
+
+	f, err := Parse(strings.NewReader(source), WithDialect(DialectGitLab))
+	require.NoError(t, err)
+
+	errors := Check(f)
+	require.Len(t, errors, 2)
+
+	assert.Equal(t, ErrorKindInvalidOwner, errors[0].Kind)
+	assert.Equal(t, 3, errors[0].Line)
+	assert.Equal(t, "invalid", errors[0].Token())
+
+	assert.Equal(t, ErrorKindMissingOwners, errors[1].Kind)
+	assert.Equal(t, 6, errors[1].Line)
+}
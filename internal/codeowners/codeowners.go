@@ -1,19 +1,18 @@
 package codeowners
 
 import (
+	"errors"
+	"fmt"
 	_fs "io/fs"
-
-	"github.com/hairyhenderson/go-codeowners"
+	"sort"
+	"strings"
 )
 
-func Find(fs _fs.FS) string {
-	// Based on https://docs.github.com/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners
-	lookup := []string{
-		".github/CODEOWNERS",
-		"CODEOWNERS",
-		"docs/CODEOWNERS",
-	}
-	for _, path := range lookup {
+// ErrNotFound is returned when no CODEOWNERS file is found.
+var ErrNotFound = errors.New("CODEOWNERS not found")
+
+func Find(fs _fs.FS, opts ...Option) string {
+	for _, path := range Locations(opts...) {
 		if fileExists(fs, path) {
 			return path
 		}
@@ -21,6 +20,25 @@ func Find(fs _fs.FS) string {
 	return ""
 }
 
+// Locations returns the paths where a CODEOWNERS file may be found in the order they are searched.
+func Locations(opts ...Option) []string {
+	if o := newOptions(opts); o.dialect == DialectGitLab {
+		// Based on https://docs.gitlab.com/ee/user/project/codeowners/#codeowners-file
+		return []string{
+			"CODEOWNERS",
+			"docs/CODEOWNERS",
+			".gitlab/CODEOWNERS",
+		}
+	}
+
+	// Based on https://docs.github.com/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners
+	return []string{
+		".github/CODEOWNERS",
+		"CODEOWNERS",
+		"docs/CODEOWNERS",
+	}
+}
+
 func fileExists(fs _fs.FS, path string) bool {
 	var err error
 	var stat _fs.FileInfo
@@ -37,25 +55,118 @@ func fileExists(fs _fs.FS, path string) bool {
 	return err == nil && !stat.IsDir()
 }
 
+// Codeowners evaluates the rules in a CODEOWNERS file.
 type Codeowners struct {
-	source *codeowners.Codeowners
+	File *File
+
+	rules []compiledRule
 }
 
+type compiledRule struct {
+	rule    *Rule
+	pattern *Pattern
+	section string
+}
+
+// New compiles the rules in a parsed CODEOWNERS file.
+func New(f *File) (*Codeowners, error) {
+	c := &Codeowners{
+		File: f,
+	}
+
+	for _, rule := range f.Rules() {
+		pattern, err := CompilePattern(rule.Pattern.Text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", rule.Line, err)
+		}
+
+		// GitLab combines sections with the same name regardless of case.
+		section := ""
+		if rule.Section != nil {
+			section = strings.ToLower(rule.Section.Name)
+		}
+
+		c.rules = append(c.rules, compiledRule{
+			rule:    rule,
+			pattern: pattern,
+			section: section,
+		})
+	}
+
+	return c, nil
+}
+
+// Owners returns the owners of path, or nil if no rule matches or the matching rules have no owners.
+// For GitLab, the owners from the matching rule in each section are combined.
 func (c Codeowners) Owners(path string) []string {
-	return c.source.Owners(path)
+	var owners []string
+	seen := make(map[string]bool)
+	for _, rule := range c.Winners(path) {
+		for _, owner := range rule.EffectiveOwners() {
+			if key := strings.ToLower(owner); !seen[key] {
+				seen[key] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+
+	return owners
 }
 
-func Open(fs _fs.FS, path string) (*Codeowners, error) {
-	f, err := fs.Open(path)
-	if err != nil {
-		return nil, err
+// Winners returns the rule that determines the owners of path in each section, in the order the rules appear.
+// Files without sections, like those GitHub supports, have at most one winner.
+func (c Codeowners) Winners(path string) []*Rule {
+	winners := make(map[string]int)
+	for i, r := range c.rules {
+		if r.pattern.Match(path) {
+			winners[r.section] = i
+		}
+	}
+
+	indices := make([]int, 0, len(winners))
+	for _, i := range winners {
+		indices = append(indices, i)
 	}
+	sort.Ints(indices)
+
+	rules := make([]*Rule, len(indices))
+	for i, index := range indices {
+		rules[i] = c.rules[index].rule
+	}
+
+	return rules
+}
 
-	if c, err := codeowners.FromReader(f, ""); err != nil {
+// Match returns the last rule that matches path, or nil if no rule matches.
+// For GitLab, use Winners to get the matching rule from each section.
+func (c Codeowners) Match(path string) *Rule {
+	// The last matching rule takes precedence.
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.Match(path) {
+			return c.rules[i].rule
+		}
+	}
+
+	return nil
+}
+
+// Matches returns every rule that matches path in the order they appear. The last rule determines the owners.
+func (c Codeowners) Matches(path string) []*Rule {
+	var rules []*Rule
+	for _, r := range c.rules {
+		if r.pattern.Match(path) {
+			rules = append(rules, r.rule)
+		}
+	}
+
+	return rules
+}
+
+func Open(fs _fs.FS, path string, opts ...Option) (*Codeowners, error) {
+	f, err := ParseFS(fs, path, opts...)
+	if err != nil {
 		return nil, err
-	} else {
-		return &Codeowners{
-			source: c,
-		}, nil
 	}
+
+	return New(f)
 }
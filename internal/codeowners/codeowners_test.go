@@ -8,6 +8,7 @@ import (
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFind(t *testing.T) {
@@ -86,6 +87,91 @@ func TestOpen(t *testing.T) {
 	assert.Equal(t, []string{"@writers"}, c.Owners("docs/README.md"))
 }
 
+func TestCodeowners_Matches(t *testing.T) {
+	var source = heredoc.Doc(`
+		* @heaths
+		*.md @writers
+		docs/ @writers @editors
+		docs/generated/
+	`)
+	fs := fstest.MapFS{
+		"CODEOWNERS": {Data: []byte(source)},
+	}
+	c, err := Open(fs, "CODEOWNERS")
+	require.NoError(t, err)
+
+	lines := func(rules []*Rule) []int {
+		var lines []int
+		for _, rule := range rules {
+			lines = append(lines, rule.Line)
+		}
+		return lines
+	}
+
+	assert.Equal(t, []int{1}, lines(c.Matches("main.go")))
+	assert.Equal(t, 1, c.Match("main.go").Line)
+
+	assert.Equal(t, []int{1, 2, 3}, lines(c.Matches("docs/README.md")))
+	assert.Equal(t, 3, c.Match("docs/README.md").Line)
+	assert.Equal(t, []string{"@writers", "@editors"}, c.Owners("docs/README.md"))
+
+	assert.Equal(t, []int{1, 3, 4}, lines(c.Matches("docs/generated/api.go")))
+	assert.Nil(t, c.Owners("docs/generated/api.go"))
+
+	empty, err := New(&File{})
+	require.NoError(t, err)
+	assert.Nil(t, empty.Match("main.go"))
+	assert.Nil(t, empty.Owners("main.go"))
+}
+
+func TestCodeowners_gitLab(t *testing.T) {
+	var source = heredoc.Doc(`
+		* @default
+		[Documentation] @docs-team
+		docs/
+		*.md @writers
+		[Development]
+		*.go @developers
+		docs/ @developers
+		[documentation]
+		docs/internal/ @internal
+	`)
+	fs := fstest.MapFS{
+		".gitlab/CODEOWNERS": {Data: []byte(source)},
+	}
+
+	path := Find(fs, WithDialect(DialectGitLab))
+	require.Equal(t, ".gitlab/CODEOWNERS", path)
+	assert.Empty(t, Find(fs))
+
+	c, err := Open(fs, path, WithDialect(DialectGitLab))
+	require.NoError(t, err)
+
+	lines := func(rules []*Rule) []int {
+		var lines []int
+		for _, rule := range rules {
+			lines = append(lines, rule.Line)
+		}
+		return lines
+	}
+
+	assert.Equal(t, []int{1}, lines(c.Winners("LICENSE")))
+	assert.Equal(t, []string{"@default"}, c.Owners("LICENSE"))
+
+	assert.Equal(t, []int{1, 6}, lines(c.Winners("main.go")))
+	assert.Equal(t, []string{"@default", "@developers"}, c.Owners("main.go"))
+
+	assert.Equal(t, []int{1, 4, 7}, lines(c.Winners("docs/README.md")))
+	assert.Equal(t, []string{"@default", "@writers", "@developers"}, c.Owners("docs/README.md"))
+
+	// Sections with the same name are combined regardless of case.
+	assert.Equal(t, []int{1, 7, 9}, lines(c.Winners("docs/internal/guide.txt")))
+	assert.Equal(t, []string{"@default", "@developers", "@internal"}, c.Owners("docs/internal/guide.txt"))
+
+	assert.Equal(t, []int{1, 3, 7}, lines(c.Winners("docs/guide.txt")))
+	assert.Equal(t, []string{"@default", "@docs-team", "@developers"}, c.Owners("docs/guide.txt"))
+}
+
 type baseFS map[string]baseFileInfo
 
 func (fs baseFS) Open(name string) (_fs.File, error) {
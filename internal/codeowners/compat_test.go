@@ -0,0 +1,117 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	legacy "github.com/hairyhenderson/go-codeowners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOwners_compat compares owners with github.com/hairyhenderson/go-codeowners, which was used before patterns were compiled here.
+// Known differences are where that library does not follow the .gitignore rules GitHub documents for CODEOWNERS.
+func TestOwners_compat(t *testing.T) {
+	patterns := []string{
+		"*",
+		"*.js",
+		"*.min.js",
+		"/*.md",
+		"README.md",
+		"/README.md",
+		"/build/logs/",
+		"/scripts/",
+		"scripts",
+		"apps/",
+		"/docs/",
+		"docs/*",
+		"docs/*.md",
+		"docs/**",
+		"src/*.go",
+		"**/logs",
+		"**/test/**",
+		"lib/**/*.rb",
+		"a/**/b",
+		"a/b/c",
+		"file?.txt",
+		`path\ with\ spaces/`,
+	}
+
+	paths := []string{
+		".github/CODEOWNERS",
+		"README.md",
+		"main.go",
+		"app.js",
+		"app.jsx",
+		"x.min.js",
+		"src/app.js",
+		"src/main.go",
+		"src/nested/main.go",
+		"build/logs/a.log",
+		"build/logs/nested/b.log",
+		"src/build/logs/a.log",
+		"scripts/a.sh",
+		"src/scripts/a.sh",
+		"apps/main.go",
+		"src/apps/main.go",
+		"myapps/main.go",
+		"docs/README.md",
+		"docs/getting-started.md",
+		"docs/build-app/troubleshooting.md",
+		"src/docs/README.md",
+		"logs/a.log",
+		"deeply/nested/logs/a.log",
+		"mylogs/a.log",
+		"test/a.go",
+		"x/test/y/z.go",
+		"lib/y.rb",
+		"lib/x/y.rb",
+		"a/b",
+		"a/xb",
+		"a/x/b",
+		"a/x/y/b/c.txt",
+		"a/b/c",
+		"a/b/c/d",
+		"x/a/b/c",
+		"file1.txt",
+		"file10.txt",
+		"dir/file2.txt",
+		"path with spaces/file.txt",
+	}
+
+	// A slash at the beginning or middle of a pattern anchors it to the repository root,
+	// and "?" matches any one character except "/".
+	differences := map[[2]string]bool{
+		{"docs/*", "src/docs/README.md"}:  false,
+		{"docs/**", "src/docs/README.md"}: false,
+		{"a/**/b", "x/a/b/c"}:             false,
+		{"a/b/c", "x/a/b/c"}:              false,
+		{"file?.txt", "file1.txt"}:        true,
+		{"file?.txt", "dir/file2.txt"}:    true,
+	}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			source := pattern + " @owner\n"
+
+			old, err := legacy.FromReader(strings.NewReader(source), "")
+			require.NoError(t, err)
+
+			f, err := Parse(strings.NewReader(source))
+			require.NoError(t, err)
+			c, err := New(f)
+			require.NoError(t, err)
+
+			for _, path := range paths {
+				got := len(c.Owners(path)) > 0
+				if want, ok := differences[[2]string{pattern, path}]; ok {
+					assert.Equal(t, want, got, "expected %q to match %q: %v", pattern, path, want)
+					continue
+				}
+
+				want := len(old.Owners(path)) > 0
+				assert.Equal(t, want, got, "expected %q to match %q like go-codeowners: %v", pattern, path, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package codeowners
+
+import (
+	"sort"
+	"strings"
+)
+
+// ChangeKind describes how the owners of a file changed.
+type ChangeKind string
+
+const (
+	ChangeKindGained  ChangeKind = "gained"
+	ChangeKindLost    ChangeKind = "lost"
+	ChangeKindChanged ChangeKind = "changed"
+)
+
+// Change is a change in the owners of a file between two CODEOWNERS files.
+type Change struct {
+	Kind      ChangeKind `json:"kind"`
+	Path      string     `json:"path"`
+	OldOwners []string   `json:"oldOwners"`
+	NewOwners []string   `json:"newOwners"`
+}
+
+// Diff compares the owners of each file between the before and after CODEOWNERS files, sorted by path.
+// Owners are compared case-insensitively regardless of order.
+func Diff(before, after *Codeowners, files []string) []Change {
+	var changes []Change
+	for _, file := range files {
+		oldOwners := before.Owners(file)
+		newOwners := after.Owners(file)
+
+		var kind ChangeKind
+		switch {
+		case len(oldOwners) == 0 && len(newOwners) == 0:
+			continue
+		case len(oldOwners) == 0:
+			kind = ChangeKindGained
+		case len(newOwners) == 0:
+			kind = ChangeKindLost
+		case !sameOwners(oldOwners, newOwners):
+			kind = ChangeKindChanged
+		default:
+			continue
+		}
+
+		changes = append(changes, Change{
+			Kind:      kind,
+			Path:      file,
+			OldOwners: nonNil(oldOwners),
+			NewOwners: nonNil(newOwners),
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+func sameOwners(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	owners := make(map[string]bool, len(a))
+	for _, owner := range a {
+		owners[strings.ToLower(owner)] = true
+	}
+
+	for _, owner := range b {
+		if !owners[strings.ToLower(owner)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func nonNil(owners []string) []string {
+	if owners == nil {
+		return []string{}
+	}
+	return owners
+}
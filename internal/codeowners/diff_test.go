@@ -0,0 +1,79 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	before := newCodeowners(t, heredoc.Doc(`
+		* @heaths
+		docs/ @writers
+		old/ @heaths
+		*.md @Writers @heaths
+	`))
+
+	after := newCodeowners(t, heredoc.Doc(`
+		* @heaths
+		docs/ @writers
+		src/ @org/core
+		*.md @heaths @writers
+		old/
+	`))
+
+	files := []string{
+		"src/main.go",
+		"README.md",
+		"docs/index.html",
+		"old/main.go",
+		"main.go",
+	}
+
+	assert.Equal(t, []Change{
+		{
+			Kind:      ChangeKindLost,
+			Path:      "old/main.go",
+			OldOwners: []string{"@heaths"},
+			NewOwners: []string{},
+		},
+		{
+			Kind:      ChangeKindChanged,
+			Path:      "src/main.go",
+			OldOwners: []string{"@heaths"},
+			NewOwners: []string{"@org/core"},
+		},
+	}, Diff(before, after, files))
+
+	assert.Equal(t, []Change{
+		{
+			Kind:      ChangeKindGained,
+			Path:      "old/main.go",
+			OldOwners: []string{},
+			NewOwners: []string{"@heaths"},
+		},
+		{
+			Kind:      ChangeKindChanged,
+			Path:      "src/main.go",
+			OldOwners: []string{"@org/core"},
+			NewOwners: []string{"@heaths"},
+		},
+	}, Diff(after, before, files))
+
+	assert.Empty(t, Diff(before, before, files))
+}
+
+func newCodeowners(t *testing.T, source string) *Codeowners {
+	t.Helper()
+
+	f, err := Parse(strings.NewReader(source))
+	require.NoError(t, err)
+
+	c, err := New(f)
+	require.NoError(t, err)
+
+	return c
+}
@@ -1,6 +1,7 @@
 package codeowners
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 	"unicode"
@@ -13,7 +14,12 @@ import (
 type ErrorKind string
 
 const (
-	ErrorKindUnknownOwner ErrorKind = "Unknown owner"
+	ErrorKindUnknownOwner    ErrorKind = "Unknown owner"
+	ErrorKindInvalidOwner    ErrorKind = "Invalid owner"
+	ErrorKindInvalidPattern  ErrorKind = "Invalid pattern"
+	ErrorKindMissingOwners   ErrorKind = "Missing owners"
+	ErrorKindNoMatchingFiles ErrorKind = "No matching files"
+	ErrorKindShadowedRule    ErrorKind = "Shadowed rule"
 )
 
 type Error struct {
@@ -25,15 +31,24 @@ type Error struct {
 	Message string    `json:"message"`
 }
 
-func (e Error) UnknownOwner() string {
-	if e.Kind == ErrorKindUnknownOwner && e.Column > 0 {
-		owner := e.Source[e.Column-1:]
-		if idx := strings.IndexFunc(owner, func(r rune) bool {
+// Token returns the text in Source starting at Column up to the next whitespace.
+func (e Error) Token() string {
+	if e.Column > 0 && e.Column <= len(e.Source) {
+		token := e.Source[e.Column-1:]
+		if idx := strings.IndexFunc(token, func(r rune) bool {
 			return unicode.IsSpace(r)
 		}); idx > 0 {
-			return owner[:idx]
+			return token[:idx]
 		}
-		return owner
+		return token
+	}
+
+	return ""
+}
+
+func (e Error) UnknownOwner() string {
+	if e.Kind == ErrorKindUnknownOwner {
+		return e.Token()
 	}
 
 	return ""
@@ -70,10 +85,23 @@ func (e Errors) UnknownOwners() []string {
 	return owners
 }
 
-func (e Errors) indexUnknownOwners() map[int][]string {
-	index := make(map[int][]string, len(e))
+// Sort sorts errors by path, line, and column.
+func (e Errors) Sort() {
+	sort.SliceStable(e, func(i, j int) bool {
+		if e[i].Path != e[j].Path {
+			return e[i].Path < e[j].Path
+		}
+		if e[i].Line != e[j].Line {
+			return e[i].Line < e[j].Line
+		}
+		return e[i].Column < e[j].Column
+	})
+}
+
+func (e Errors) indexLines() map[int]Errors {
+	index := make(map[int]Errors, len(e))
 	for _, e := range e {
-		index[e.Line] = append(index[e.Line], e.UnknownOwner())
+		index[e.Line] = append(index[e.Line], e)
 	}
 
 	return index
@@ -101,3 +129,15 @@ func QueryErrors(client api.GQLClient, repo repository.Repository, ref string) (
 
 	return query.Repository.Codeowners.Errors, nil
 }
+
+func newError(kind ErrorKind, path string, line, column int, source, detail string) Error {
+	pad := strings.Repeat(" ", column-1)
+	return Error{
+		Kind:    kind,
+		Path:    path,
+		Line:    line,
+		Column:  column,
+		Source:  source,
+		Message: fmt.Sprintf("%s on line %d: %s\n\n  %s\n  %s^", kind, line, detail, source, pad),
+	}
+}
@@ -0,0 +1,30 @@
+package codeowners
+
+import (
+	_fs "io/fs"
+)
+
+// ListFiles returns the slash-separated paths of all files in fs, excluding the .git directory.
+func ListFiles(fs _fs.FS) ([]string, error) {
+	var files []string
+	err := _fs.WalkDir(fs, ".", func(path string, d _fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return _fs.SkipDir
+			}
+			return nil
+		}
+
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
@@ -0,0 +1,22 @@
+package codeowners
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFiles(t *testing.T) {
+	fs := fstest.MapFS{
+		".git/HEAD":          {Data: []byte("ref: refs/heads/main")},
+		".github/CODEOWNERS": {Data: []byte("* @heaths")},
+		"docs/README.md":     {Data: []byte("# README")},
+		"main.go":            {Data: []byte("package main")},
+	}
+
+	files, err := ListFiles(fs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{".github/CODEOWNERS", "docs/README.md", "main.go"}, files)
+}
@@ -0,0 +1,78 @@
+package codeowners
+
+import (
+	"bytes"
+	"fmt"
+	_fs "io/fs"
+	"sort"
+	"strings"
+)
+
+// Fix removes unknown owners from the CODEOWNERS file at the lines and columns reported in errors.
+// It returns the fixed content, or nil if there was nothing to fix, and errors for any rules left without owners.
+func Fix(fs _fs.FS, errors Errors) ([]byte, Errors, error) {
+	path := errors.Path()
+	if path == "" {
+		path = Find(fs)
+	}
+	if path == "" {
+		return nil, nil, ErrNotFound
+	}
+
+	index := make(map[int]Errors)
+	for _, e := range errors {
+		if e.Kind == ErrorKindUnknownOwner && e.Column > 0 {
+			index[e.Line] = append(index[e.Line], e)
+		}
+	}
+	if len(index) == 0 {
+		return nil, nil, nil
+	}
+
+	content, err := _fs.ReadFile(fs, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Keep line endings so the rest of the file is written back unchanged.
+	lines := bytes.SplitAfter(content, []byte("\n"))
+
+	var missing Errors
+	for linenum := 1; linenum <= len(lines); linenum++ {
+		errs, ok := index[linenum]
+		if !ok {
+			continue
+		}
+
+		line := string(lines[linenum-1])
+		text := strings.TrimRight(line, "\r\n")
+		ending := line[len(text):]
+
+		// Remove owners from right to left so earlier columns remain valid.
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Column > errs[j].Column
+		})
+		for _, e := range errs {
+			owner := e.UnknownOwner()
+			start := e.Column - 1
+			if start >= len(text) || !strings.HasPrefix(text[start:], owner) {
+				return nil, nil, fmt.Errorf("%s line %d does not contain owner %q at column %d", path, linenum, owner, e.Column)
+			}
+
+			// Remove whitespace preceding the owner but keep any following it e.g., before a comment.
+			end := start + len(owner)
+			for start > 0 && isSpace(text[start-1]) {
+				start--
+			}
+			text = text[:start] + text[end:]
+		}
+
+		if rule := parseLine(linenum, text, ending, DialectGitHub).Rule; rule == nil || len(rule.Owners) == 0 {
+			missing = append(missing, newError(ErrorKindMissingOwners, path, linenum, 1, text, "all owners were removed so matching files have no owners"))
+		}
+
+		lines[linenum-1] = []byte(text + ending)
+	}
+
+	return bytes.Join(lines, nil), missing, nil
+}
@@ -0,0 +1,146 @@
+package codeowners
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFix(t *testing.T) {
+	var source = heredoc.Doc(`
+		# License
+
+		* @default # Default owner(s)
+		docs/** @writers @unknown
+		*.md	@unknown   # Documentation
+	`)
+
+	const path = ".github/CODEOWNERS"
+
+	tests := []struct {
+		name        string
+		source      string
+		errors      Errors
+		want        string
+		wantMissing []int
+		wantErr     string
+	}{
+		{
+			name:   "no errors",
+			source: source,
+		},
+		{
+			name:   "unknown owner",
+			source: source,
+			errors: Errors{
+				{
+					Kind:   ErrorKindUnknownOwner,
+					Line:   4,
+					Column: 18,
+					Source: "docs/** @writers @unknown",
+					Path:   path,
+				},
+			},
+			want: heredoc.Doc(`
+				# License
+
+				* @default # Default owner(s)
+				docs/** @writers
+				*.md	@unknown   # Documentation
+			`),
+		},
+		{
+			name:   "missing owners",
+			source: source,
+			errors: Errors{
+				{
+					Kind:   ErrorKindUnknownOwner,
+					Line:   4,
+					Column: 18,
+					Source: "docs/** @writers @unknown",
+					Path:   path,
+				},
+				{
+					Kind:   ErrorKindUnknownOwner,
+					Line:   5,
+					Column: 6,
+					Source: "*.md\t@unknown   # Documentation",
+					Path:   path,
+				},
+			},
+			want: heredoc.Doc(`
+				# License
+
+				* @default # Default owner(s)
+				docs/** @writers
+				*.md   # Documentation
+			`),
+			wantMissing: []int{5},
+		},
+		{
+			name:   "multiple owners on a line",
+			source: "* @a @b @c\r\ndocs/ @d\r\n",
+			errors: Errors{
+				{
+					Kind:   ErrorKindUnknownOwner,
+					Line:   1,
+					Column: 3,
+					Source: "* @a @b @c",
+					Path:   path,
+				},
+				{
+					Kind:   ErrorKindUnknownOwner,
+					Line:   1,
+					Column: 9,
+					Source: "* @a @b @c",
+					Path:   path,
+				},
+			},
+			want: "* @b\r\ndocs/ @d\r\n",
+		},
+		{
+			name:   "changed",
+			source: "* @a\n",
+			errors: Errors{
+				{
+					Kind:   ErrorKindUnknownOwner,
+					Line:   1,
+					Column: 3,
+					Source: "* @b",
+					Path:   path,
+				},
+			},
+			wantErr: `.github/CODEOWNERS line 1 does not contain owner "@b" at column 3`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := fstest.MapFS{
+				path: {Data: []byte(tt.source)},
+			}
+
+			got, missing, err := Fix(fs, tt.errors)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			if tt.want == "" {
+				assert.Nil(t, got)
+			} else {
+				assert.Equal(t, tt.want, string(got))
+			}
+
+			var lines []int
+			for _, e := range missing {
+				assert.Equal(t, ErrorKindMissingOwners, e.Kind)
+				lines = append(lines, e.Line)
+			}
+			assert.Equal(t, tt.wantMissing, lines)
+		})
+	}
+}
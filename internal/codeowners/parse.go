@@ -0,0 +1,439 @@
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	_fs "io/fs"
+	"strconv"
+	"strings"
+)
+
+// LineKind describes the content of a line in a CODEOWNERS file.
+type LineKind int
+
+const (
+	LineBlank LineKind = iota
+	LineComment
+	LineRule
+	LineSection
+)
+
+// Dialect is the syntax and semantics of a CODEOWNERS file.
+type Dialect string
+
+const (
+	// DialectGitHub is the default CODEOWNERS syntax supported by GitHub.
+	DialectGitHub Dialect = "github"
+
+	// DialectGitLab supports sections, optional sections, approval counts, and default section owners.
+	DialectGitLab Dialect = "gitlab"
+)
+
+// Dialects returns the names of all supported dialects.
+func Dialects() []string {
+	return []string{string(DialectGitHub), string(DialectGitLab)}
+}
+
+// Option configures how a CODEOWNERS file is found and parsed.
+type Option func(*options)
+
+type options struct {
+	dialect Dialect
+}
+
+// WithDialect sets the dialect of the CODEOWNERS file. The default is DialectGitHub.
+func WithDialect(dialect Dialect) Option {
+	return func(o *options) {
+		if dialect != "" {
+			o.dialect = dialect
+		}
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		dialect: DialectGitHub,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (k LineKind) String() string {
+	switch k {
+	case LineBlank:
+		return "blank"
+	case LineComment:
+		return "comment"
+	case LineRule:
+		return "rule"
+	case LineSection:
+		return "section"
+	default:
+		return "unknown"
+	}
+}
+
+// File is a parsed CODEOWNERS file that retains the original text of every line.
+type File struct {
+	Path    string
+	Dialect Dialect
+	Lines   []*Line
+}
+
+// Line is a single line of a CODEOWNERS file.
+type Line struct {
+	Number int
+	Kind   LineKind
+
+	// Text is the original text of the line without the line ending.
+	Text string
+
+	// Ending is the original line ending, which is empty for the last line if the file does not end with a newline.
+	Ending string
+
+	// Rule is set when Kind is LineRule.
+	Rule *Rule
+
+	// Section is set when Kind is LineSection.
+	Section *Section
+
+	// Comment is set for comment lines, and rules or sections with a trailing comment.
+	Comment *Token
+}
+
+// Token is a span of text on a line.
+type Token struct {
+	Text   string
+	Line   int
+	Column int
+}
+
+// EndColumn returns the column immediately following the token.
+func (t Token) EndColumn() int {
+	return t.Column + len(t.Text)
+}
+
+// Rule is a pattern followed by zero or more owners.
+type Rule struct {
+	Line    int
+	Pattern Token
+	Owners  []Token
+	Comment *Token
+
+	// Section is the GitLab section containing the rule, or nil if the rule precedes any sections.
+	Section *Section
+}
+
+// OwnerNames returns the text of each owner.
+func (r *Rule) OwnerNames() []string {
+	return tokenTexts(r.Owners)
+}
+
+// EffectiveOwners returns the owners of the rule, or the default owners of its section if the rule has no owners.
+func (r *Rule) EffectiveOwners() []string {
+	if len(r.Owners) == 0 && r.Section != nil {
+		return r.Section.OwnerNames()
+	}
+	return r.OwnerNames()
+}
+
+// Section is a GitLab section header e.g., "^[Section name][2] @default-owner".
+type Section struct {
+	Line int
+
+	// Header is the section header without default owners e.g., "^[Section name][2]".
+	Header Token
+
+	Name      string
+	Optional  bool
+	Approvals int
+	Owners    []Token
+	Comment   *Token
+}
+
+// OwnerNames returns the text of each default owner.
+func (s *Section) OwnerNames() []string {
+	return tokenTexts(s.Owners)
+}
+
+func tokenTexts(tokens []Token) []string {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	texts := make([]string, len(tokens))
+	for i, token := range tokens {
+		texts[i] = token.Text
+	}
+	return texts
+}
+
+// CommentBlock is a run of consecutive comment lines.
+type CommentBlock struct {
+	Start    int
+	End      int
+	Comments []Token
+}
+
+// Rules returns all rules in the order they appear.
+func (f *File) Rules() []*Rule {
+	var rules []*Rule
+	for _, line := range f.Lines {
+		if line.Rule != nil {
+			rules = append(rules, line.Rule)
+		}
+	}
+	return rules
+}
+
+// Sections returns all GitLab sections in the order they appear.
+func (f *File) Sections() []*Section {
+	var sections []*Section
+	for _, line := range f.Lines {
+		if line.Section != nil {
+			sections = append(sections, line.Section)
+		}
+	}
+	return sections
+}
+
+// Comments returns blocks of consecutive comment lines. Trailing comments on rules are not included.
+func (f *File) Comments() []CommentBlock {
+	var blocks []CommentBlock
+	var block *CommentBlock
+	for _, line := range f.Lines {
+		if line.Kind != LineComment {
+			block = nil
+			continue
+		}
+
+		if block == nil {
+			blocks = append(blocks, CommentBlock{Start: line.Number})
+			block = &blocks[len(blocks)-1]
+		}
+		block.End = line.Number
+		block.Comments = append(block.Comments, *line.Comment)
+	}
+	return blocks
+}
+
+// Line returns the line with the given 1-based number, or nil if out of range.
+func (f *File) Line(number int) *Line {
+	if number < 1 || number > len(f.Lines) {
+		return nil
+	}
+	return f.Lines[number-1]
+}
+
+// Bytes returns the original content of the file.
+func (f *File) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = f.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo writes the original content of the file to w.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, line := range f.Lines {
+		n, err := io.WriteString(w, line.Text+line.Ending)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Parse parses a CODEOWNERS file from r.
+func Parse(r io.Reader, opts ...Option) (*File, error) {
+	o := newOptions(opts)
+	f := &File{
+		Dialect: o.dialect,
+	}
+
+	var section *Section
+	reader := bufio.NewReader(r)
+	for number := 1; ; number++ {
+		s, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if s == "" {
+			break
+		}
+
+		text := strings.TrimSuffix(s, "\n")
+		text = strings.TrimSuffix(text, "\r")
+		line := parseLine(number, text, s[len(text):], o.dialect)
+		if line.Section != nil {
+			section = line.Section
+		} else if line.Rule != nil {
+			line.Rule.Section = section
+		}
+		f.Lines = append(f.Lines, line)
+
+		if err != nil {
+			break
+		}
+	}
+	return f, nil
+}
+
+// ParseFS parses the CODEOWNERS file at path within fs.
+func ParseFS(fs _fs.FS, path string, opts ...Option) (*File, error) {
+	file, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	f, err := Parse(file, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Path = path
+	return f, nil
+}
+
+const bom = "\ufeff"
+
+func parseLine(number int, text, ending string, dialect Dialect) *Line {
+	line := &Line{
+		Number: number,
+		Kind:   LineBlank,
+		Text:   text,
+		Ending: ending,
+	}
+
+	start := 0
+	if number == 1 && strings.HasPrefix(text, bom) {
+		start = len(bom)
+	}
+
+	if dialect == DialectGitLab {
+		if section, end := parseSection(number, text, start); section != nil {
+			section.Owners, section.Comment = tokenize(number, text, end)
+			line.Kind = LineSection
+			line.Section = section
+			line.Comment = section.Comment
+			return line
+		}
+	}
+
+	tokens, comment := tokenize(number, text, start)
+	line.Comment = comment
+
+	if len(tokens) > 0 {
+		line.Kind = LineRule
+		line.Rule = &Rule{
+			Line:    number,
+			Pattern: tokens[0],
+			Owners:  tokens[1:],
+			Comment: comment,
+		}
+		if len(line.Rule.Owners) == 0 {
+			line.Rule.Owners = nil
+		}
+	} else if comment != nil {
+		line.Kind = LineComment
+	}
+
+	return line
+}
+
+// parseSection parses a GitLab section header and returns the section and the index following the header,
+// or nil if the line is not a section header.
+func parseSection(number int, text string, start int) (*Section, int) {
+	i := start
+	for i < len(text) && isSpace(text[i]) {
+		i++
+	}
+	begin := i
+
+	optional := false
+	if strings.HasPrefix(text[i:], "^") {
+		optional = true
+		i++
+	}
+
+	if !strings.HasPrefix(text[i:], "[") {
+		return nil, 0
+	}
+
+	end := strings.IndexByte(text[i:], ']')
+	if end < 0 {
+		return nil, 0
+	}
+
+	name := text[i+1 : i+end]
+	i += end + 1
+
+	approvals := 0
+	if strings.HasPrefix(text[i:], "[") {
+		if end := strings.IndexByte(text[i:], ']'); end > 0 {
+			if n, err := strconv.Atoi(text[i+1 : i+end]); err == nil && n >= 0 {
+				approvals = n
+				i += end + 1
+			}
+		}
+	}
+
+	return &Section{
+		Line: number,
+		Header: Token{
+			Text:   text[begin:i],
+			Line:   number,
+			Column: begin + 1,
+		},
+		Name:      name,
+		Optional:  optional,
+		Approvals: approvals,
+	}, i
+}
+
+// tokenize splits text starting at index start into whitespace-separated tokens and an optional trailing comment.
+// Whitespace escaped with a backslash is part of a token.
+func tokenize(number int, text string, start int) ([]Token, *Token) {
+	var tokens []Token
+	for i := start; i < len(text); {
+		if isSpace(text[i]) {
+			i++
+			continue
+		}
+
+		if text[i] == '#' {
+			return tokens, &Token{
+				Text:   text[i:],
+				Line:   number,
+				Column: i + 1,
+			}
+		}
+
+		j := i
+		for j < len(text) && !isSpace(text[j]) {
+			if text[j] == '\\' && j+1 < len(text) {
+				j++
+			}
+			j++
+		}
+
+		tokens = append(tokens, Token{
+			Text:   text[i:j],
+			Line:   number,
+			Column: i + 1,
+		})
+		i = j
+	}
+
+	return tokens, nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
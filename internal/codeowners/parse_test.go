@@ -0,0 +1,200 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	var source = heredoc.Doc(`
+		# License
+		# Second line
+
+		* @default # Default owner(s)
+		docs/**   @writers	@editors
+		path\ with\ spaces/ @heaths
+		unowned/
+	`)
+
+	f, err := Parse(strings.NewReader(source))
+	require.NoError(t, err)
+	require.Len(t, f.Lines, 7)
+
+	kinds := make([]LineKind, len(f.Lines))
+	for i, line := range f.Lines {
+		assert.Equal(t, i+1, line.Number)
+		kinds[i] = line.Kind
+	}
+	assert.Equal(t, []LineKind{LineComment, LineComment, LineBlank, LineRule, LineRule, LineRule, LineRule}, kinds)
+
+	rules := f.Rules()
+	require.Len(t, rules, 4)
+
+	assert.Equal(t, Token{Text: "*", Line: 4, Column: 1}, rules[0].Pattern)
+	assert.Equal(t, []Token{{Text: "@default", Line: 4, Column: 3}}, rules[0].Owners)
+	assert.Equal(t, &Token{Text: "# Default owner(s)", Line: 4, Column: 12}, rules[0].Comment)
+
+	assert.Equal(t, Token{Text: "docs/**", Line: 5, Column: 1}, rules[1].Pattern)
+	assert.Equal(t, []string{"@writers", "@editors"}, rules[1].OwnerNames())
+	assert.Equal(t, 11, rules[1].Owners[0].Column)
+	assert.Equal(t, 20, rules[1].Owners[1].Column)
+	assert.Nil(t, rules[1].Comment)
+
+	assert.Equal(t, `path\ with\ spaces/`, rules[2].Pattern.Text)
+	assert.Equal(t, 20, rules[2].Pattern.EndColumn())
+
+	assert.Equal(t, "unowned/", rules[3].Pattern.Text)
+	assert.Nil(t, rules[3].Owners)
+
+	assert.Equal(t, []CommentBlock{
+		{
+			Start: 1,
+			End:   2,
+			Comments: []Token{
+				{Text: "# License", Line: 1, Column: 1},
+				{Text: "# Second line", Line: 2, Column: 1},
+			},
+		},
+	}, f.Comments())
+
+	assert.Equal(t, source, string(f.Bytes()))
+}
+
+func TestParse_roundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		lines  int
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "no trailing newline",
+			source: "* @heaths",
+			lines:  1,
+		},
+		{
+			name:   "CRLF",
+			source: "# comment\r\n* @heaths\r\n\r\n",
+			lines:  3,
+		},
+		{
+			name:   "mixed line endings",
+			source: "* @heaths\r\ndocs/ @writers\n  \t\n",
+			lines:  3,
+		},
+		{
+			name:   "BOM",
+			source: "\ufeff* @heaths\n",
+			lines:  1,
+		},
+		{
+			name:   "trailing whitespace",
+			source: "* @heaths   \n# comment \t\n",
+			lines:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(strings.NewReader(tt.source))
+			require.NoError(t, err)
+			assert.Len(t, f.Lines, tt.lines)
+
+			var sb strings.Builder
+			n, err := f.WriteTo(&sb)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.source)), n)
+			assert.Equal(t, tt.source, sb.String())
+		})
+	}
+}
+
+func TestParse_BOM(t *testing.T) {
+	f, err := Parse(strings.NewReader("\ufeff* @heaths\n"))
+	require.NoError(t, err)
+
+	rules := f.Rules()
+	require.Len(t, rules, 1)
+	assert.Equal(t, "*", rules[0].Pattern.Text)
+	assert.Equal(t, 4, rules[0].Pattern.Column)
+}
+
+func TestParseFS(t *testing.T) {
+	fs := fstest.MapFS{
+		".github/CODEOWNERS": {Data: []byte("* @heaths\n")},
+	}
+
+	f, err := ParseFS(fs, ".github/CODEOWNERS")
+	require.NoError(t, err)
+	assert.Equal(t, ".github/CODEOWNERS", f.Path)
+	assert.Equal(t, 1, len(f.Rules()))
+	assert.Equal(t, LineRule, f.Line(1).Kind)
+	assert.Nil(t, f.Line(2))
+
+	_, err = ParseFS(fs, "CODEOWNERS")
+	assert.Error(t, err)
+}
+
+func TestParse_gitLab(t *testing.T) {
+	var source = heredoc.Doc(`
+		* @default
+		[Documentation] @docs-team # Docs
+		docs/
+		README.md @lead
+		^[Optional Reviews][2] @reviewers
+		*.go
+		[Not a section
+	`)
+
+	f, err := Parse(strings.NewReader(source), WithDialect(DialectGitLab))
+	require.NoError(t, err)
+	assert.Equal(t, DialectGitLab, f.Dialect)
+
+	kinds := make([]LineKind, len(f.Lines))
+	for i, line := range f.Lines {
+		kinds[i] = line.Kind
+	}
+	assert.Equal(t, []LineKind{LineRule, LineSection, LineRule, LineRule, LineSection, LineRule, LineRule}, kinds)
+
+	sections := f.Sections()
+	require.Len(t, sections, 2)
+
+	assert.Equal(t, "Documentation", sections[0].Name)
+	assert.Equal(t, Token{Text: "[Documentation]", Line: 2, Column: 1}, sections[0].Header)
+	assert.False(t, sections[0].Optional)
+	assert.Equal(t, 0, sections[0].Approvals)
+	assert.Equal(t, []string{"@docs-team"}, sections[0].OwnerNames())
+	assert.Equal(t, "# Docs", sections[0].Comment.Text)
+
+	assert.Equal(t, "Optional Reviews", sections[1].Name)
+	assert.Equal(t, "^[Optional Reviews][2]", sections[1].Header.Text)
+	assert.True(t, sections[1].Optional)
+	assert.Equal(t, 2, sections[1].Approvals)
+
+	rules := f.Rules()
+	require.Len(t, rules, 5)
+	assert.Nil(t, rules[0].Section)
+	assert.Equal(t, sections[0], rules[1].Section)
+	assert.Equal(t, []string{"@docs-team"}, rules[1].EffectiveOwners())
+	assert.Equal(t, []string{"@lead"}, rules[2].EffectiveOwners())
+	assert.Equal(t, sections[1], rules[3].Section)
+	assert.Equal(t, []string{"@reviewers"}, rules[3].EffectiveOwners())
+	assert.Equal(t, "[Not", rules[4].Pattern.Text)
+
+	assert.Equal(t, source, string(f.Bytes()))
+}
+
+func TestParse_gitHubSections(t *testing.T) {
+	f, err := Parse(strings.NewReader("[Documentation] @docs-team\n"))
+	require.NoError(t, err)
+	assert.Equal(t, DialectGitHub, f.Dialect)
+	assert.Empty(t, f.Sections())
+	assert.Equal(t, "[Documentation]", f.Rules()[0].Pattern.Text)
+}
@@ -0,0 +1,118 @@
+package codeowners
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Pattern matches repository paths using CODEOWNERS syntax, which follows most of the same rules as .gitignore.
+type Pattern struct {
+	text string
+	re   *regexp.Regexp
+}
+
+// CompilePattern compiles a pattern from a CODEOWNERS rule.
+func CompilePattern(pattern string) (*Pattern, error) {
+	expr := patternExpr(pattern)
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pattern{
+		text: pattern,
+		re:   re,
+	}, nil
+}
+
+// Match returns true if the pattern matches the slash-separated path relative to the repository root.
+func (p *Pattern) Match(path string) bool {
+	path = strings.TrimPrefix(path, "./")
+	path = strings.TrimPrefix(path, "/")
+	return p.re.MatchString(path)
+}
+
+func (p *Pattern) String() string {
+	return p.text
+}
+
+func patternExpr(pattern string) string {
+	// A trailing slash only matches directories, so only match paths within them.
+	dirOnly := false
+	if strings.HasSuffix(pattern, "/") && !strings.HasSuffix(pattern, `\/`) {
+		dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	// A leading or middle slash anchors the pattern to the repository root.
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(?:.*/)?")
+	}
+
+	segments := strings.Split(pattern, "/")
+	last := len(segments) - 1
+	for i, segment := range segments {
+		if segment == "**" {
+			if i == last {
+				sb.WriteString(".+")
+			} else {
+				sb.WriteString("(?:.*/)?")
+			}
+			continue
+		}
+
+		sb.WriteString(globExpr(segment))
+		if i < last {
+			sb.WriteString("/")
+		}
+	}
+
+	switch {
+	case segments[last] == "**":
+		sb.WriteString("$")
+	case dirOnly:
+		sb.WriteString("/.+$")
+	case segments[last] == "*" && last > 0:
+		// Unlike .gitignore, "docs/*" matches files directly within docs but not subdirectories.
+		sb.WriteString("$")
+	default:
+		sb.WriteString("(?:/.*)?$")
+	}
+
+	return sb.String()
+}
+
+func globExpr(glob string) string {
+	var sb, literal strings.Builder
+	flush := func() {
+		sb.WriteString(regexp.QuoteMeta(literal.String()))
+		literal.Reset()
+	}
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				literal.WriteByte(glob[i])
+			}
+		case '*':
+			flush()
+			sb.WriteString("[^/]*")
+		case '?':
+			flush()
+			sb.WriteString("[^/]")
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flush()
+
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPattern_Match(t *testing.T) {
+	// Based on examples from https://docs.github.com/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{
+			pattern: "*",
+			match:   []string{"main.go", "docs/README.md", ".github/CODEOWNERS"},
+		},
+		{
+			pattern: "*.js",
+			match:   []string{"app.js", "src/app.js"},
+			noMatch: []string{"app.jsx", "src/app.ts"},
+		},
+		{
+			pattern: "/build/logs/",
+			match:   []string{"build/logs/a.log", "build/logs/nested/b.log"},
+			noMatch: []string{"build/logs", "src/build/logs/a.log"},
+		},
+		{
+			pattern: "docs/*",
+			match:   []string{"docs/getting-started.md"},
+			noMatch: []string{"docs/build-app/troubleshooting.md", "src/docs/README.md"},
+		},
+		{
+			pattern: "apps/",
+			match:   []string{"apps/main.go", "src/apps/main.go", "src/apps/nested/main.go"},
+			noMatch: []string{"apps", "myapps/main.go"},
+		},
+		{
+			pattern: "/docs/",
+			match:   []string{"docs/README.md", "docs/nested/README.md"},
+			noMatch: []string{"src/docs/README.md"},
+		},
+		{
+			pattern: "**/logs",
+			match:   []string{"logs", "logs/a.log", "build/logs/a.log", "deeply/nested/logs/a.log"},
+			noMatch: []string{"mylogs/a.log"},
+		},
+		{
+			pattern: "docs/**",
+			match:   []string{"docs/README.md", "docs/nested/README.md"},
+			noMatch: []string{"docs", "src/docs/README.md"},
+		},
+		{
+			pattern: "a/**/b",
+			match:   []string{"a/b", "a/x/b", "a/x/y/b/c.txt"},
+			noMatch: []string{"a/xb"},
+		},
+		{
+			pattern: "file?.txt",
+			match:   []string{"file1.txt", "dir/file2.txt"},
+			noMatch: []string{"file10.txt", "file/.txt"},
+		},
+		{
+			pattern: `path\ with\ spaces/`,
+			match:   []string{"path with spaces/file.txt"},
+			noMatch: []string{`path\ with\ spaces/file.txt`},
+		},
+		{
+			pattern: "README.md",
+			match:   []string{"README.md", "./README.md", "/README.md", "docs/README.md"},
+			noMatch: []string{"README.mdx", "xREADME.md"},
+		},
+		{
+			pattern: "ä/*.md",
+			match:   []string{"ä/README.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := CompilePattern(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.pattern, p.String())
+
+			for _, path := range tt.match {
+				assert.True(t, p.Match(path), "expected %q to match %q", tt.pattern, path)
+			}
+			for _, path := range tt.noMatch {
+				assert.False(t, p.Match(path), "expected %q to not match %q", tt.pattern, path)
+			}
+		})
+	}
+}
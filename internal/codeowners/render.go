@@ -1,34 +1,35 @@
 package codeowners
 
 import (
-	"bufio"
 	"fmt"
 	_fs "io/fs"
-	"strings"
+	"sort"
 
 	"github.com/heaths/go-console"
 )
 
 type RenderOptions struct {
 	Console console.Console
+	Dialect Dialect
 	Fix     bool
 
 	Color struct {
 		Comment string
 		Error   string
+		Section string
 	}
 }
 
 func Render(fs _fs.FS, errors Errors, opts RenderOptions) error {
 	path := errors.Path()
 	if path == "" {
-		path = Find(fs)
+		path = Find(fs, WithDialect(opts.Dialect))
 	}
 	if path == "" {
 		return nil
 	}
 
-	file, err := fs.Open(path)
+	f, err := ParseFS(fs, path, WithDialect(opts.Dialect))
 	if err != nil {
 		return err
 	}
@@ -36,25 +37,27 @@ func Render(fs _fs.FS, errors Errors, opts RenderOptions) error {
 	cs := opts.Console.ColorScheme()
 	remove := cs.ColorFunc(opts.Color.Error)
 	comment := cs.ColorFunc(opts.Color.Comment)
+	section := cs.ColorFunc(opts.Color.Section)
 
-	linenum := 0
-	missing := errors.indexUnknownOwners()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		linenum++
-
-		line := scanner.Text()
+	index := errors.indexLines()
+	for _, l := range f.Lines {
+		line := l.Text
 
+		// Color from right to left so earlier columns remain valid.
 		if opts.Console.IsStdoutTTY() {
-			if owners, ok := missing[linenum]; ok {
-				for _, owner := range owners {
-					line = strings.ReplaceAll(line, owner, remove(owner))
-				}
+			if l.Comment != nil {
+				idx := l.Comment.Column - 1
+				line = line[:idx] + comment(line[idx:])
 			}
 
-			if idx := strings.IndexRune(line, '#'); idx >= 0 {
-				line = line[:idx] + comment(line[idx:])
+			if errs, ok := index[l.Number]; ok {
+				line = highlight(line, errs, remove)
+			}
+
+			if l.Section != nil && index[l.Number] == nil {
+				header := l.Section.Header
+				idx := header.Column - 1
+				line = line[:idx] + section(header.Text) + line[idx+len(header.Text):]
 			}
 		}
 
@@ -63,3 +66,25 @@ func Render(fs _fs.FS, errors Errors, opts RenderOptions) error {
 
 	return nil
 }
+
+// highlight colors the token at each error's column from right to left so earlier columns remain valid.
+func highlight(line string, errors Errors, color func(string) string) string {
+	errors = append(Errors(nil), errors...)
+	sort.Slice(errors, func(i, j int) bool {
+		return errors[i].Column > errors[j].Column
+	})
+
+	end := len(line)
+	for _, e := range errors {
+		token := e.Token()
+		start := e.Column - 1
+		if token == "" || start+len(token) > end || line[start:start+len(token)] != token {
+			continue
+		}
+
+		line = line[:start] + color(token) + line[start+len(token):]
+		end = start
+	}
+
+	return line
+}
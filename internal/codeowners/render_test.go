@@ -10,6 +10,40 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestRender_gitLab(t *testing.T) {
+	var source = heredoc.Doc(`
+		* @default
+		^[Documentation][2] @writers # Docs
+		docs/
+	`)
+
+	mockFS := fstest.MapFS{
+		"CODEOWNERS": {Data: []byte(source)},
+	}
+
+	stdout := &bytes.Buffer{}
+	con := console.Fake(
+		console.WithStdout(stdout),
+		console.WithStdoutTTY(true),
+	)
+
+	opts := RenderOptions{
+		Console: con,
+		Dialect: DialectGitLab,
+	}
+	opts.Color.Comment = "#00FF00"
+	opts.Color.Error = "#FF0000"
+	opts.Color.Section = "#0000FF"
+
+	err := Render(mockFS, nil, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, heredoc.Docf(`
+		* @default
+		%[1]s[0;38;2;0;0;255m^[Documentation][2]%[1]s[0m @writers %[1]s[0;38;2;0;255;0m# Docs%[1]s[0m
+		docs/
+	`, "\033"), stdout.String())
+}
+
 func TestRender(t *testing.T) {
 	var source = heredoc.Doc(`
 		# License
@@ -62,6 +96,32 @@ func TestRender(t *testing.T) {
 				docs/** @writers %[1]s[0;38;2;255;0;0m@unknown%[1]s[0m
 			`, "\033"),
 		},
+		{
+			name: "missing owners and unknown owner (tty)",
+			errors: Errors{
+				{
+					Kind:   ErrorKindMissingOwners,
+					Line:   4,
+					Column: 1,
+					Source: "docs/** @writers @unknown",
+					Path:   path,
+				},
+				{
+					Kind:   ErrorKindUnknownOwner,
+					Line:   4,
+					Column: 18,
+					Source: "docs/** @writers @unknown",
+					Path:   path,
+				},
+			},
+			tty: true,
+			want: heredoc.Docf(`
+				%[1]s[0;38;2;0;255;0m# License%[1]s[0m
+
+				* @default %[1]s[0;38;2;0;255;0m# Default owner(s)%[1]s[0m
+				%[1]s[0;38;2;255;0;0mdocs/**%[1]s[0m @writers %[1]s[0;38;2;255;0;0m@unknown%[1]s[0m
+			`, "\033"),
+		},
 		{
 			name: "no errors",
 			want: source,
@@ -81,9 +141,11 @@ func TestRender(t *testing.T) {
 				Color: struct {
 					Comment string
 					Error   string
+					Section string
 				}{
 					Comment: "#00FF00",
 					Error:   "#FF0000",
+					Section: "#0000FF",
 				},
 			}
 
@@ -0,0 +1,234 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RefFS returns a read-only fs.FS of the tree at ref in the repository at dir.
+func RefFS(dir, ref string) fs.FS {
+	return &refFS{
+		dir: dir,
+		ref: ref,
+	}
+}
+
+type refFS struct {
+	dir string
+	ref string
+}
+
+func (f *refFS) Open(name string) (fs.File, error) {
+	entry, err := f.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.isDir {
+		entries, err := f.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &dirFile{entry: entry, entries: entries}, nil
+	}
+
+	stdout, _, err := Exec("-C", f.dir, "cat-file", "blob", entry.object)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return &blobFile{entry: entry, reader: bytes.NewReader(stdout.Bytes())}, nil
+}
+
+func (f *refFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	treeish := f.ref + ":"
+	if name != "." {
+		treeish += name
+	}
+
+	entries, err := f.lsTree(treeish)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	dirEntries := make([]fs.DirEntry, len(entries))
+	for i, entry := range entries {
+		dirEntries[i] = entry
+	}
+
+	return dirEntries, nil
+}
+
+func (f *refFS) Stat(name string) (fs.FileInfo, error) {
+	return f.stat("stat", name)
+}
+
+func (f *refFS) stat(op, name string) (*treeEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name == "." {
+		return &treeEntry{name: ".", isDir: true}, nil
+	}
+
+	entries, err := f.lsTree(f.ref, "--", name)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	for _, entry := range entries {
+		if entry.path == name {
+			return entry, nil
+		}
+	}
+
+	return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+func (f *refFS) lsTree(args ...string) ([]*treeEntry, error) {
+	args = append([]string{"-C", f.dir, "ls-tree", "-z", "--long"}, args...)
+	stdout, _, err := Exec(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*treeEntry
+	for _, line := range strings.Split(stdout.String(), "\x00") {
+		// Each line is "<mode> <type> <object> <size>\t<path>".
+		info, p, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(info)
+		if len(fields) != 4 {
+			continue
+		}
+
+		// Submodules are commits in another repository.
+		typ := fields[1]
+		if typ != "blob" && typ != "tree" {
+			continue
+		}
+
+		size, _ := strconv.ParseInt(fields[3], 10, 64)
+		entries = append(entries, &treeEntry{
+			name:   path.Base(p),
+			path:   p,
+			object: fields[2],
+			size:   size,
+			isDir:  typ == "tree",
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+
+	return entries, nil
+}
+
+type treeEntry struct {
+	name   string
+	path   string
+	object string
+	size   int64
+	isDir  bool
+}
+
+func (e *treeEntry) Name() string {
+	return e.name
+}
+
+func (e *treeEntry) Size() int64 {
+	return e.size
+}
+
+func (e *treeEntry) Mode() fs.FileMode {
+	if e.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (e *treeEntry) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (e *treeEntry) IsDir() bool {
+	return e.isDir
+}
+
+func (e *treeEntry) Sys() any {
+	return nil
+}
+
+func (e *treeEntry) Type() fs.FileMode {
+	return e.Mode().Type()
+}
+
+func (e *treeEntry) Info() (fs.FileInfo, error) {
+	return e, nil
+}
+
+type blobFile struct {
+	entry  *treeEntry
+	reader *bytes.Reader
+}
+
+func (f *blobFile) Stat() (fs.FileInfo, error) {
+	return f.entry, nil
+}
+
+func (f *blobFile) Read(b []byte) (int, error) {
+	return f.reader.Read(b)
+}
+
+func (f *blobFile) Close() error {
+	return nil
+}
+
+type dirFile struct {
+	entry   *treeEntry
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (f *dirFile) Stat() (fs.FileInfo, error) {
+	return f.entry, nil
+}
+
+func (f *dirFile) Read(b []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: f.entry.path, Err: errors.New("is a directory")}
+}
+
+func (f *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries := f.entries[f.offset:]
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	f.offset += len(entries)
+
+	if n > 0 && len(entries) == 0 {
+		return nil, io.EOF
+	}
+
+	return entries, nil
+}
+
+func (f *dirFile) Close() error {
+	return nil
+}
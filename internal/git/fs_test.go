@@ -0,0 +1,70 @@
+package git
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heaths/gh-codeowners/internal/gittest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefFS(t *testing.T) {
+	dir := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS":    "* @heaths\n",
+		"docs/index.md": "# Docs\n",
+	})
+
+	// Changes to the working tree are not in the ref.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte("* @nobody\n"), 0644))
+
+	sha, err := ResolveRef(dir, "HEAD")
+	require.NoError(t, err)
+	assert.Len(t, sha, 40)
+
+	refFS := RefFS(dir, sha)
+
+	content, err := fs.ReadFile(refFS, "CODEOWNERS")
+	require.NoError(t, err)
+	assert.Equal(t, "* @heaths\n", string(content))
+
+	stat, err := fs.Stat(refFS, "docs/index.md")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), stat.Size())
+	assert.False(t, stat.IsDir())
+
+	stat, err = fs.Stat(refFS, "docs")
+	require.NoError(t, err)
+	assert.True(t, stat.IsDir())
+
+	_, err = fs.Stat(refFS, "missing")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	var files []string
+	err = fs.WalkDir(refFS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CODEOWNERS", "docs/index.md"}, files)
+
+	files, err = ListTree(dir, sha)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CODEOWNERS", "docs/index.md"}, files)
+}
+
+func TestResolveRef(t *testing.T) {
+	dir := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": "* @heaths\n",
+	})
+
+	_, err := ResolveRef(dir, "missing")
+	assert.Error(t, err)
+
+	_, err = ResolveRef(dir, "--all")
+	assert.Error(t, err)
+}
@@ -2,9 +2,8 @@ package git
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
-	"io/fs"
-	"os"
 	"os/exec"
 	"strings"
 
@@ -31,22 +30,195 @@ func Exec(args ...string) (stdout, stderr bytes.Buffer, err error) {
 	return
 }
 
-func RootFS() (fs.FS, error) {
+func RootDir() (string, error) {
 	stdout, _, err := Exec("rev-parse", "--show-toplevel")
 	if err != nil {
-		return nil, fmt.Errorf("failed to find git root: %w", err)
+		return "", fmt.Errorf("failed to find git root: %w", err)
 	}
 
 	path := strings.TrimSpace(stdout.String())
-	return os.DirFS(path), nil
+	return path, nil
 }
 
-func RefName() (string, error) {
-	stdout, _, err := Exec("rev-parse", "HEAD")
+// ResolveRef returns the commit SHA for a branch, tag, or commit in the repository at dir.
+func ResolveRef(dir, ref string) (string, error) {
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid ref %q", ref)
+	}
+
+	stdout, _, err := Exec("-C", dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ref %q: %w", ref, err)
+	}
+
+	sha := strings.TrimSpace(stdout.String())
+	return sha, nil
+}
+
+// IsPushed returns whether the commit is contained in any remote-tracking branch.
+// Remote-tracking branches are only updated by fetch, pull, and push, so the result may be stale until the next fetch.
+func IsPushed(dir, sha string) (bool, error) {
+	stdout, _, err := Exec("-C", dir, "branch", "--remotes", "--contains", sha, "--format=%(refname)")
+	if err != nil {
+		return false, fmt.Errorf("failed to find remote branches: %w", err)
+	}
+
+	return strings.TrimSpace(stdout.String()) != "", nil
+}
+
+// MergeBase returns the best common ancestor of ref and its upstream branch.
+func MergeBase(dir, ref string) (string, error) {
+	stdout, _, err := Exec("-C", dir, "merge-base", ref, ref+"@{upstream}")
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base: %w", err)
+	}
+
+	sha := strings.TrimSpace(stdout.String())
+	return sha, nil
+}
+
+// Differs returns whether the file at path differs between two commits.
+func Differs(dir, a, b, path string) (bool, error) {
+	_, _, err := Exec("-C", dir, "diff", "--quiet", a, b, "--", path)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to compare %s: %w", path, err)
+	}
+
+	return false, nil
+}
+
+// FileStatus is the status of a changed file in the index and working tree.
+type FileStatus struct {
+	Path     string
+	OrigPath string
+
+	// Staged and Unstaged are the status codes e.g., 'M' for modified or ' ' for unchanged.
+	// Untracked files are '?' for both.
+	Staged   byte
+	Unstaged byte
+}
+
+// Status returns the status of changed files in the repository at dir.
+func Status(dir string) ([]FileStatus, error) {
+	stdout, _, err := Exec("-C", dir, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+
+	var files []FileStatus
+	entries := splitNull(stdout.String())
+	for i := 0; i < len(entries); i++ {
+		// Each entry is "XY path" followed by the original path for renames and copies.
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		status := FileStatus{
+			Path:     entry[3:],
+			Staged:   entry[0],
+			Unstaged: entry[1],
+		}
+
+		if (status.Staged == 'R' || status.Staged == 'C') && i+1 < len(entries) {
+			i++
+			status.OrigPath = entries[i]
+		}
+
+		files = append(files, status)
+	}
+
+	return files, nil
+}
+
+// Commit is a commit and the files it changed.
+type Commit struct {
+	SHA     string
+	Subject string
+	Files   []CommitFile
+}
+
+// CommitFile is a file changed by a commit.
+type CommitFile struct {
+	Path string
+
+	// Status is the status code e.g., 'A' for added or 'M' for modified.
+	// Renames are reported as a deleted and an added file.
+	Status byte
+}
+
+// Log returns the commits in revRange, newest first, and the files each commit changed.
+// Merge commits are included but do not list any files.
+func Log(dir, revRange string) ([]Commit, error) {
+	if strings.HasPrefix(revRange, "-") {
+		return nil, fmt.Errorf("invalid revision range %q", revRange)
+	}
+
+	// Prefix each commit with a byte that cannot start a status code to separate commits from files.
+	stdout, _, err := Exec("-C", dir, "log", "-z", "--name-status", "--no-renames", "--format=%x01%H%x00%s", revRange, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to log %s: %w", revRange, err)
+	}
+
+	var commits []Commit
+	entries := splitNull(stdout.String())
+	for i := 0; i < len(entries); i++ {
+		entry := strings.TrimLeft(entries[i], "\n")
+		if strings.HasPrefix(entry, "\x01") {
+			commit := Commit{
+				SHA: entry[1:],
+			}
+			if i+1 < len(entries) {
+				i++
+				commit.Subject = entries[i]
+			}
+			commits = append(commits, commit)
+			continue
+		}
+
+		if entry == "" || len(commits) == 0 || i+1 >= len(entries) {
+			continue
+		}
+
+		i++
+		commit := &commits[len(commits)-1]
+		commit.Files = append(commit.Files, CommitFile{
+			Path:   entries[i],
+			Status: entry[0],
+		})
+	}
+
+	return commits, nil
+}
+
+func ListFiles(dir string) ([]string, error) {
+	stdout, _, err := Exec("-C", dir, "ls-files", "-z")
 	if err != nil {
-		return "", fmt.Errorf("failed to get ref name: %w", err)
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return splitNull(stdout.String()), nil
+}
+
+// ListTree returns the slash-separated paths of files in the tree at ref in the repository at dir.
+func ListTree(dir, ref string) ([]string, error) {
+	stdout, _, err := Exec("-C", dir, "ls-tree", "-r", "-z", "--name-only", ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return splitNull(stdout.String()), nil
+}
+
+func splitNull(s string) []string {
+	files := strings.Split(s, "\x00")
+	if n := len(files); n > 0 && files[n-1] == "" {
+		files = files[:n-1]
 	}
 
-	name := strings.TrimSpace(stdout.String())
-	return name, nil
+	return files
 }
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heaths/gh-codeowners/internal/gittest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeBase(t *testing.T) {
+	origin := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": "* @heaths\n",
+	})
+
+	dir := filepath.Join(t.TempDir(), "clone")
+	gittest.Run(t, "", "clone", "--quiet", origin, dir)
+
+	base, err := ResolveRef(dir, "HEAD")
+	require.NoError(t, err)
+
+	pushed, err := IsPushed(dir, base)
+	require.NoError(t, err)
+	assert.True(t, pushed)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte("* @nobody\n"), 0644))
+	gittest.Commit(t, dir, "Change owners")
+
+	head, err := ResolveRef(dir, "HEAD")
+	require.NoError(t, err)
+
+	pushed, err = IsPushed(dir, head)
+	require.NoError(t, err)
+	assert.False(t, pushed)
+
+	got, err := MergeBase(dir, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, base, got)
+
+	differs, err := Differs(dir, base, head, "CODEOWNERS")
+	require.NoError(t, err)
+	assert.True(t, differs)
+
+	differs, err = Differs(dir, base, base, "CODEOWNERS")
+	require.NoError(t, err)
+	assert.False(t, differs)
+}
+
+func TestStatus(t *testing.T) {
+	dir := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": "* @heaths\n",
+		"main.go":    "package main\n",
+		"old.go":     "package main\n",
+		"docs/a.md":  "# A\n",
+	})
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // changed\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "new file.md"), []byte("# New\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "a.md"), []byte("# A (staged)\n"), 0644))
+
+	gittest.Run(t, dir, "add", "docs/a.md")
+	gittest.Run(t, dir, "mv", "old.go", "new.go")
+
+	got, err := Status(dir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []FileStatus{
+		{Path: "docs/a.md", Staged: 'M', Unstaged: ' '},
+		{Path: "main.go", Staged: ' ', Unstaged: 'M'},
+		{Path: "new.go", OrigPath: "old.go", Staged: 'R', Unstaged: ' '},
+		{Path: "docs/new file.md", Staged: '?', Unstaged: '?'},
+	}, got)
+}
+
+func TestListFiles(t *testing.T) {
+	dir := gittest.NewRepo(t, map[string]string{
+		".gitignore":  "*.log\n",
+		"CODEOWNERS":  "* @heaths\n",
+		"src/main.go": "package main\n",
+	})
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "debug.log"), []byte("ignored\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "new.go"), []byte("package main\n"), 0644))
+
+	got, err := ListFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{".gitignore", "CODEOWNERS", "src/main.go"}, got)
+}
+
+func TestLog(t *testing.T) {
+	dir := gittest.NewRepo(t, map[string]string{
+		"CODEOWNERS": "* @heaths\n",
+		"main.go":    "package main\n",
+		"old.go":     "package main\n",
+	})
+
+	base, err := ResolveRef(dir, "HEAD")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // changed\n"), 0644))
+	gittest.Run(t, dir, "mv", "old.go", "new file.go")
+	gittest.Commit(t, dir, "Rename old.go")
+
+	head, err := ResolveRef(dir, "HEAD")
+	require.NoError(t, err)
+
+	got, err := Log(dir, base+"..HEAD")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, head, got[0].SHA)
+	assert.Equal(t, "Rename old.go", got[0].Subject)
+	assert.ElementsMatch(t, []CommitFile{
+		{Path: "main.go", Status: 'M'},
+		{Path: "new file.go", Status: 'A'},
+		{Path: "old.go", Status: 'D'},
+	}, got[0].Files)
+
+	got, err = Log(dir, "HEAD")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, base, got[1].SHA)
+	assert.Len(t, got[1].Files, 3)
+
+	_, err = Log(dir, "--all")
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+// Package gittest creates git repositories for tests.
+//
+// It runs git directly instead of using the git package so tests in that package can use it.
+package gittest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// NewRepo writes files to a new temporary directory and commits them to a new git repository.
+// Keys are slash-separated paths relative to the repository root.
+func NewRepo(t testing.TB, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	WriteFiles(t, dir, files)
+	Init(t, dir)
+
+	return dir
+}
+
+// Init commits all files in dir to a new git repository.
+// The test is skipped if git is not installed.
+func Init(t testing.TB, dir string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip(err)
+	}
+
+	Run(t, dir, "init", "--quiet")
+	Run(t, dir, "add", "--all")
+	Commit(t, dir, "Initial commit")
+}
+
+// Commit commits all changes to tracked files in the repository at dir.
+func Commit(t testing.TB, dir, message string) {
+	t.Helper()
+
+	Run(t, dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--all", "--message", message)
+}
+
+// WriteFiles writes files to dir, creating any parent directories.
+// Keys are slash-separated paths relative to dir.
+func WriteFiles(t testing.TB, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Run runs git with args in dir, or the current directory if dir is empty.
+func Run(t testing.TB, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
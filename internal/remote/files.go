@@ -0,0 +1,92 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	_fs "io/fs"
+	"net/url"
+
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/cli/go-gh/pkg/repository"
+)
+
+// ErrTruncated is returned by ListFiles when the repository has too many files to list at once.
+var ErrTruncated = errors.New("too many files to list")
+
+// File is a file in a repository tree.
+type File struct {
+	Path string
+	Size int64
+}
+
+// ListFiles returns all files in the repository at ref with their slash-separated paths and sizes using a single request.
+func ListFiles(client api.RESTClient, repo repository.Repository, ref string) ([]File, error) {
+	var response struct {
+		Tree []struct {
+			Path string
+			Type string
+			Size int64
+		}
+		Truncated bool
+	}
+
+	path := fmt.Sprintf("repos/%s/%s/git/trees/%s?recursive=1", repo.Owner(), repo.Name(), url.PathEscape(ref))
+	err := client.Get(path, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Truncated {
+		return nil, ErrTruncated
+	}
+
+	var files []File
+	for _, entry := range response.Tree {
+		if entry.Type == "blob" {
+			files = append(files, File{
+				Path: entry.Path,
+				Size: entry.Size,
+			})
+		}
+	}
+
+	return files, nil
+}
+
+// Files returns all files in fs using a single request to ListFiles,
+// or by reading each directory if the repository has too many files to list at once.
+func (fs *FS) Files(client api.RESTClient) ([]File, error) {
+	ref, err := fs.Ref()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ListFiles(client, fs.repo, ref)
+	if !errors.Is(err, ErrTruncated) {
+		return files, err
+	}
+
+	files = nil
+	err = _fs.WalkDir(fs, ".", func(path string, d _fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		// Entries read from their parent tree already have their size.
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, File{
+			Path: path,
+			Size: info.Size(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
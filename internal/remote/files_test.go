@@ -0,0 +1,116 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/cli/go-gh"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestListFiles(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	gock.New("https://api.github.com").
+		Get("/repos/heaths/gh-codeowners/git/trees/main").
+		MatchParam("recursive", "1").
+		Reply(200).
+		JSON(`{
+			"tree": [
+				{"path": "CODEOWNERS", "type": "blob", "size": 10},
+				{"path": "docs", "type": "tree"},
+				{"path": "docs/index.md", "type": "blob", "size": 6},
+				{"path": "vendor", "type": "commit"}
+			],
+			"truncated": false
+		}`)
+
+	gock.New("https://api.github.com").
+		Get("/repos/heaths/gh-codeowners/git/trees/large").
+		Reply(200).
+		JSON(`{"tree": [], "truncated": true}`)
+
+	client, err := gh.RESTClient(&api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "***",
+	})
+	require.NoError(t, err)
+
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	files, err := ListFiles(client, repo, "main")
+	require.NoError(t, err)
+	assert.Equal(t, []File{{Path: "CODEOWNERS", Size: 10}, {Path: "docs/index.md", Size: 6}}, files)
+
+	_, err = ListFiles(client, repo, "large")
+	assert.ErrorIs(t, err, ErrTruncated)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestFS_Files(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	gock.New("https://api.github.com").
+		Get("/repos/heaths/gh-codeowners/git/trees/large").
+		MatchParam("recursive", "1").
+		Reply(200).
+		JSON(`{"tree": [], "truncated": true}`)
+
+	// Sizes are read from each directory without querying each file.
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"expression":"large:"`).
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"object": {
+						"__typename": "Tree",
+						"entries": [
+							{"name": "docs", "type": "tree", "object": {}},
+							{"name": "CODEOWNERS", "type": "blob", "object": {"byteSize": 10}}
+						]
+					}
+				}
+			}
+		}`)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"expression":"large:docs"`).
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"object": {
+						"__typename": "Tree",
+						"entries": [
+							{"name": "index.md", "type": "blob", "object": {"byteSize": 6}}
+						]
+					}
+				}
+			}
+		}`)
+
+	opts := &api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "***",
+	}
+	rest, err := gh.RESTClient(opts)
+	require.NoError(t, err)
+	gql, err := gh.GQLClient(opts)
+	require.NoError(t, err)
+
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	files, err := New(gql, repo, "large").Files(rest)
+	require.NoError(t, err)
+	assert.Equal(t, []File{{Path: "CODEOWNERS", Size: 10}, {Path: "docs/index.md", Size: 6}}, files)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,334 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	_fs "io/fs"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/shurcooL/graphql"
+)
+
+var errNoContent = errors.New("content is binary or too large")
+
+// FS is a read-only fs.FS of a repository at a given ref backed by the GitHub GraphQL API.
+type FS struct {
+	client api.GQLClient
+	repo   repository.Repository
+	ref    string
+
+	// Objects are cached by path since Find, Open, and Stat often request the same paths.
+	objects map[string]*object
+}
+
+// New creates an FS for the repository at ref. If ref is empty, the repository's default branch is used.
+func New(client api.GQLClient, repo repository.Repository, ref string) *FS {
+	return &FS{
+		client:  client,
+		repo:    repo,
+		ref:     ref,
+		objects: make(map[string]*object),
+	}
+}
+
+// Ref returns the ref the FS reads from, querying the repository's default branch if no ref was specified.
+func (fs *FS) Ref() (string, error) {
+	if fs.ref != "" {
+		return fs.ref, nil
+	}
+
+	var query struct {
+		Repository struct {
+			DefaultBranchRef *struct {
+				Name string
+			}
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner": graphql.String(fs.repo.Owner()),
+		"repo":  graphql.String(fs.repo.Name()),
+	}
+
+	err := fs.client.Query("DefaultBranch", &query, variables)
+	if err != nil {
+		return "", err
+	}
+
+	if query.Repository.DefaultBranchRef == nil {
+		return "", errors.New("repository has no default branch")
+	}
+
+	fs.ref = query.Repository.DefaultBranchRef.Name
+	return fs.ref, nil
+}
+
+// Open opens the named file or directory.
+func (fs *FS) Open(name string) (_fs.File, error) {
+	obj, err := fs.object("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &file{
+		object: obj,
+	}
+	if !obj.isDir {
+		f.reader = bytes.NewReader([]byte(obj.text))
+	}
+
+	return f, nil
+}
+
+// ReadDir reads the named directory and returns entries sorted by name.
+func (fs *FS) ReadDir(name string) ([]_fs.DirEntry, error) {
+	obj, err := fs.object("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !obj.isDir {
+		return nil, &_fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	return obj.readDir(), nil
+}
+
+// Stat returns information about the named file or directory.
+func (fs *FS) Stat(name string) (_fs.FileInfo, error) {
+	obj, err := fs.object("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (fs *FS) object(op, name string) (*object, error) {
+	if !_fs.ValidPath(name) {
+		return nil, &_fs.PathError{Op: op, Path: name, Err: _fs.ErrInvalid}
+	}
+
+	if obj, ok := fs.objects[name]; ok {
+		if obj == nil {
+			return nil, &_fs.PathError{Op: op, Path: name, Err: _fs.ErrNotExist}
+		}
+
+		// Entries read from a parent tree only have their size, so query the content when needed.
+		if !obj.partial || op == "stat" {
+			return obj, nil
+		}
+	}
+
+	obj, err := fs.query(name)
+	if err != nil {
+		return nil, &_fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	fs.objects[name] = obj
+	if obj == nil {
+		return nil, &_fs.PathError{Op: op, Path: name, Err: _fs.ErrNotExist}
+	}
+
+	if obj.isDir {
+		for _, entry := range obj.entries {
+			p := path.Join(name, entry.name)
+			if _, ok := fs.objects[p]; !ok {
+				fs.objects[p] = entry
+			}
+		}
+	}
+
+	return obj, nil
+}
+
+func (fs *FS) query(name string) (*object, error) {
+	ref, err := fs.Ref()
+	if err != nil {
+		return nil, err
+	}
+
+	var query struct {
+		Repository struct {
+			Object *struct {
+				Typename string `graphql:"__typename"`
+				Blob     struct {
+					ByteSize    int
+					IsBinary    bool
+					IsTruncated bool
+					Text        string
+				} `graphql:"... on Blob"`
+				Tree struct {
+					Entries []struct {
+						Name   string
+						Type   string
+						Object struct {
+							Blob struct {
+								ByteSize int
+							} `graphql:"... on Blob"`
+						}
+					}
+				} `graphql:"... on Tree"`
+			} `graphql:"object(expression: $expression)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+
+	expression := ref + ":"
+	if name != "." {
+		expression += name
+	}
+
+	variables := map[string]interface{}{
+		"owner":      graphql.String(fs.repo.Owner()),
+		"repo":       graphql.String(fs.repo.Name()),
+		"expression": graphql.String(expression),
+	}
+
+	err = fs.client.Query("RepositoryObject", &query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	o := query.Repository.Object
+	if o == nil {
+		return nil, nil
+	}
+
+	obj := &object{
+		name: path.Base(name),
+	}
+
+	switch o.Typename {
+	case "Blob":
+		obj.size = int64(o.Blob.ByteSize)
+		obj.text = o.Blob.Text
+		obj.noContent = o.Blob.IsBinary || o.Blob.IsTruncated
+	case "Tree":
+		obj.isDir = true
+		for _, entry := range o.Tree.Entries {
+			// Submodules are commits in another repository.
+			if entry.Type != "blob" && entry.Type != "tree" {
+				continue
+			}
+
+			obj.entries = append(obj.entries, &object{
+				name:    entry.Name,
+				size:    int64(entry.Object.Blob.ByteSize),
+				isDir:   entry.Type == "tree",
+				partial: true,
+			})
+		}
+		sort.Slice(obj.entries, func(i, j int) bool {
+			return obj.entries[i].name < obj.entries[j].name
+		})
+	default:
+		return nil, nil
+	}
+
+	return obj, nil
+}
+
+type object struct {
+	name      string
+	size      int64
+	isDir     bool
+	text      string
+	noContent bool
+	entries   []*object
+
+	// partial objects were read from a parent tree and have no content.
+	partial bool
+}
+
+func (o *object) Name() string {
+	return o.name
+}
+
+func (o *object) Size() int64 {
+	return o.size
+}
+
+func (o *object) Mode() _fs.FileMode {
+	if o.isDir {
+		return _fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (o *object) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (o *object) IsDir() bool {
+	return o.isDir
+}
+
+func (o *object) Sys() any {
+	return nil
+}
+
+func (o *object) Type() _fs.FileMode {
+	return o.Mode().Type()
+}
+
+func (o *object) Info() (_fs.FileInfo, error) {
+	return o, nil
+}
+
+func (o *object) readDir() []_fs.DirEntry {
+	entries := make([]_fs.DirEntry, len(o.entries))
+	for i, entry := range o.entries {
+		entries[i] = entry
+	}
+	return entries
+}
+
+type file struct {
+	*object
+
+	reader *bytes.Reader
+	offset int
+}
+
+func (f *file) Stat() (_fs.FileInfo, error) {
+	return f.object, nil
+}
+
+func (f *file) Read(b []byte) (int, error) {
+	if f.isDir {
+		return 0, &_fs.PathError{Op: "read", Path: f.name, Err: errors.New("is a directory")}
+	}
+
+	if f.noContent {
+		return 0, &_fs.PathError{Op: "read", Path: f.name, Err: errNoContent}
+	}
+
+	return f.reader.Read(b)
+}
+
+func (f *file) ReadDir(n int) ([]_fs.DirEntry, error) {
+	if !f.isDir {
+		return nil, &_fs.PathError{Op: "readdir", Path: f.name, Err: errors.New("not a directory")}
+	}
+
+	entries := f.readDir()[f.offset:]
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	f.offset += len(entries)
+
+	if n > 0 && len(entries) == 0 {
+		return nil, io.EOF
+	}
+
+	return entries, nil
+}
+
+func (f *file) Close() error {
+	return nil
+}
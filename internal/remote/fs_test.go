@@ -0,0 +1,181 @@
+package remote
+
+import (
+	_fs "io/fs"
+	"testing"
+
+	"github.com/cli/go-gh"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestFS(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`DefaultBranch`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"defaultBranchRef":{"name":"main"}}}}`)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"expression":"main:"`).
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"object": {
+						"__typename": "Tree",
+						"entries": [
+							{"name": "docs", "type": "tree", "object": {}},
+							{"name": "CODEOWNERS", "type": "blob", "object": {"byteSize": 10}},
+							{"name": "vendor", "type": "commit", "object": {}}
+						]
+					}
+				}
+			}
+		}`)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"expression":"main:docs"`).
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"object": {
+						"__typename": "Tree",
+						"entries": [
+							{"name": "index.md", "type": "blob", "object": {"byteSize": 6}}
+						]
+					}
+				}
+			}
+		}`)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"expression":"main:CODEOWNERS"`).
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"object": {
+						"__typename": "Blob",
+						"byteSize": 10,
+						"isBinary": false,
+						"isTruncated": false,
+						"text": "* @heaths\n"
+					}
+				}
+			}
+		}`)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"expression":"main:missing"`).
+		Reply(200).
+		JSON(`{"data":{"repository":{"object":null}}}`)
+
+	fs := newFS(t, "")
+
+	entries, err := _fs.ReadDir(fs, ".")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "CODEOWNERS", entries[0].Name())
+	assert.False(t, entries[0].IsDir())
+	assert.Equal(t, "docs", entries[1].Name())
+	assert.True(t, entries[1].IsDir())
+
+	// Stat uses the entry from the parent tree.
+	stat, err := _fs.Stat(fs, "CODEOWNERS")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stat.Size())
+
+	var files []string
+	err = _fs.WalkDir(fs, ".", func(path string, d _fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CODEOWNERS", "docs/index.md"}, files)
+
+	content, err := _fs.ReadFile(fs, "CODEOWNERS")
+	require.NoError(t, err)
+	assert.Equal(t, "* @heaths\n", string(content))
+
+	// Cached content is not queried again.
+	content, err = _fs.ReadFile(fs, "CODEOWNERS")
+	require.NoError(t, err)
+	assert.Equal(t, "* @heaths\n", string(content))
+
+	_, err = _fs.Stat(fs, "missing")
+	assert.ErrorIs(t, err, _fs.ErrNotExist)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestFS_ref(t *testing.T) {
+	t.Cleanup(gock.Off)
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`"expression":"v1.0:bin.dat"`).
+		Reply(200).
+		JSON(`{
+			"data": {
+				"repository": {
+					"object": {
+						"__typename": "Blob",
+						"byteSize": 1024,
+						"isBinary": true,
+						"isTruncated": false,
+						"text": null
+					}
+				}
+			}
+		}`)
+
+	fs := newFS(t, "v1.0")
+
+	ref, err := fs.Ref()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0", ref)
+
+	f, err := fs.Open("bin.dat")
+	require.NoError(t, err)
+	defer f.Close()
+
+	stat, err := f.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024), stat.Size())
+
+	_, err = f.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, errNoContent)
+
+	_, err = fs.Open("../outside")
+	assert.ErrorIs(t, err, _fs.ErrInvalid)
+
+	assert.True(t, gock.IsDone())
+}
+
+func newFS(t *testing.T, ref string) *FS {
+	t.Helper()
+
+	client, err := gh.GQLClient(&api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "***",
+	})
+	require.NoError(t, err)
+
+	repo, err := repository.Parse("heaths/gh-codeowners")
+	require.NoError(t, err)
+
+	return New(client, repo, ref)
+}
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+)
+
+const (
+	checkstyleVersion = "4.3"
+)
+
+type checkstyleLog struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// WriteCheckstyle writes errors as a checkstyle XML report with a file element for each path.
+// Files and errors are sorted by path, line, and column so the output is deterministic.
+func WriteCheckstyle(w io.Writer, errors codeowners.Errors) error {
+	sorted := make(codeowners.Errors, len(errors))
+	copy(sorted, errors)
+	sorted.Sort()
+
+	log := checkstyleLog{
+		Version: checkstyleVersion,
+		Files:   []checkstyleFile{},
+	}
+
+	for _, e := range sorted {
+		if n := len(log.Files); n == 0 || log.Files[n-1].Name != e.Path {
+			log.Files = append(log.Files, checkstyleFile{Name: e.Path})
+		}
+
+		file := &log.Files[len(log.Files)-1]
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     e.Line,
+			Column:   e.Column,
+			Severity: Level(e.Kind),
+			Message:  summary(e),
+			Source:   toolName + "." + RuleID(e.Kind),
+		})
+	}
+
+	return writeXML(w, log)
+}
+
+// summary returns the first line of the error message, or the kind if there is no message.
+func summary(e codeowners.Error) string {
+	if line, _, _ := strings.Cut(e.Message, "\n"); line != "" {
+		return line
+	}
+
+	return string(e.Kind)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
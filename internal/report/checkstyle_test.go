@@ -0,0 +1,33 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCheckstyle(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCheckstyle(&buf, testErrors))
+	assert.Equal(t, heredoc.Doc(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<checkstyle version="4.3">
+		  <file name="CODEOWNERS">
+		    <error line="2" column="1" severity="warning" message="Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3" source="gh-codeowners.shadowed-rule"></error>
+		  </file>
+		  <file name="docs,old/CODEOWNERS">
+		    <error line="1" column="5" severity="error" message="Unknown owner on line 1: make sure @nobody exists and has 100% write access" source="gh-codeowners.unknown-owner"></error>
+		  </file>
+		</checkstyle>
+	`), buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteCheckstyle(&buf, nil))
+	assert.Equal(t, heredoc.Doc(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<checkstyle version="4.3"></checkstyle>
+	`), buf.String())
+}
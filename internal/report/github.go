@@ -0,0 +1,83 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+)
+
+var (
+	dataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	propertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+	cellEscaper     = strings.NewReplacer("|", `\|`, "\r", " ", "\n", " ")
+)
+
+// WriteAnnotations writes a GitHub Actions workflow command for each error so they are shown inline on pull requests.
+func WriteAnnotations(w io.Writer, errors codeowners.Errors) error {
+	sorted := make(codeowners.Errors, len(errors))
+	copy(sorted, errors)
+	sorted.Sort()
+
+	for _, e := range sorted {
+		properties := []string{
+			"file=" + propertyEscaper.Replace(e.Path),
+			fmt.Sprintf("line=%d", e.Line),
+		}
+		if e.Column > 0 {
+			properties = append(properties, fmt.Sprintf("col=%d", e.Column))
+		}
+		properties = append(properties, "title="+propertyEscaper.Replace(string(e.Kind)))
+
+		message := e.Message
+		if message == "" {
+			message = string(e.Kind)
+		}
+
+		_, err := fmt.Fprintf(w, "::%s %s::%s\n", Level(e.Kind), strings.Join(properties, ","), dataEscaper.Replace(message))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteSummary writes a Markdown summary of errors and unknown owners for a GitHub Actions job summary.
+func WriteSummary(w io.Writer, errors codeowners.Errors) error {
+	sorted := make(codeowners.Errors, len(errors))
+	copy(sorted, errors)
+	sorted.Sort()
+
+	var sb strings.Builder
+	sb.WriteString("## CODEOWNERS\n\n")
+
+	if len(sorted) == 0 {
+		sb.WriteString("No errors found.\n\n")
+		_, err := io.WriteString(w, sb.String())
+		return err
+	}
+
+	fmt.Fprintf(&sb, "Found %d error(s).\n\n", len(sorted))
+	sb.WriteString("| File | Line | Kind | Source |\n")
+	sb.WriteString("| ---- | ---: | ---- | ------ |\n")
+	for _, e := range sorted {
+		source := ""
+		if e.Source != "" {
+			source = "`" + cellEscaper.Replace(e.Source) + "`"
+		}
+		fmt.Fprintf(&sb, "| %s | %d | %s | %s |\n", cellEscaper.Replace(e.Path), e.Line, cellEscaper.Replace(string(e.Kind)), source)
+	}
+
+	if owners := sorted.UnknownOwners(); len(owners) > 0 {
+		sb.WriteString("\n### Unknown owners\n\n")
+		for _, owner := range owners {
+			fmt.Fprintf(&sb, "- `%s`\n", owner)
+		}
+	}
+
+	sb.WriteString("\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testErrors = codeowners.Errors{
+	{
+		Kind:    codeowners.ErrorKindShadowedRule,
+		Path:    "CODEOWNERS",
+		Line:    2,
+		Column:  1,
+		Source:  "*.md @writers",
+		Message: "Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3\n\n  *.md @writers\n  ^",
+	},
+	{
+		Kind:    codeowners.ErrorKindUnknownOwner,
+		Path:    "docs,old/CODEOWNERS",
+		Line:    1,
+		Column:  5,
+		Source:  "a|b @nobody",
+		Message: "Unknown owner on line 1: make sure @nobody exists and has 100% write access",
+	},
+}
+
+func TestWriteAnnotations(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteAnnotations(&buf, testErrors))
+	assert.Equal(t, heredoc.Doc(`
+		::warning file=CODEOWNERS,line=2,col=1,title=Shadowed rule::Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3%0A%0A  *.md @writers%0A  ^
+		::error file=docs%2Cold/CODEOWNERS,line=1,col=5,title=Unknown owner::Unknown owner on line 1: make sure @nobody exists and has 100%25 write access
+	`), buf.String())
+}
+
+func TestWriteSummary(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteSummary(&buf, testErrors))
+	assert.Equal(t, heredoc.Doc(`
+		## CODEOWNERS
+
+		Found 2 error(s).
+
+		| File | Line | Kind | Source |
+		| ---- | ---: | ---- | ------ |
+		| CODEOWNERS | 2 | Shadowed rule | `+"`*.md @writers`"+` |
+		| docs,old/CODEOWNERS | 1 | Unknown owner | `+"`a\\|b @nobody`"+` |
+
+		### Unknown owners
+
+		- `+"`@nobody`"+`
+
+	`), buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteSummary(&buf, nil))
+	assert.Equal(t, "## CODEOWNERS\n\nNo errors found.\n\n", buf.String())
+}
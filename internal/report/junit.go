@@ -0,0 +1,129 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	File      string         `xml:"file,attr"`
+	Line      int            `xml:"line,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitKey struct {
+	path string
+	line int
+}
+
+// WriteJUnit writes a JUnit XML report with a test case for each rule in file so passing rules are also reported.
+// Each error is a failure of the test case for its path and line, which is added if it is not a rule in file.
+// Suites are sorted by path and test cases by line so the output is deterministic.
+func WriteJUnit(w io.Writer, file *codeowners.File, errors codeowners.Errors) error {
+	cases := make(map[junitKey]*junitTestCase)
+	add := func(path string, line int, text string) *junitTestCase {
+		key := junitKey{path: path, line: line}
+		if tc, ok := cases[key]; ok {
+			return tc
+		}
+
+		name := fmt.Sprintf("line %d", line)
+		if text = strings.TrimSpace(text); text != "" {
+			name += ": " + text
+		}
+
+		tc := &junitTestCase{
+			Name:      name,
+			ClassName: path,
+			File:      path,
+			Line:      line,
+		}
+		cases[key] = tc
+		return tc
+	}
+
+	if file != nil {
+		for _, rule := range file.Rules() {
+			text := ""
+			if line := file.Line(rule.Line); line != nil {
+				text = line.Text
+			}
+			add(file.Path, rule.Line, text)
+		}
+	}
+
+	sorted := make(codeowners.Errors, len(errors))
+	copy(sorted, errors)
+	sorted.Sort()
+
+	for _, e := range sorted {
+		tc := add(e.Path, e.Line, e.Source)
+		tc.Failures = append(tc.Failures, junitFailure{
+			Message: summary(e),
+			Type:    string(e.Kind),
+			Text:    e.Message,
+		})
+	}
+
+	keys := make([]junitKey, 0, len(cases))
+	for key := range cases {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].path != keys[j].path {
+			return keys[i].path < keys[j].path
+		}
+		return keys[i].line < keys[j].line
+	})
+
+	report := junitTestSuites{
+		Name:   toolName,
+		Suites: []junitTestSuite{},
+	}
+
+	for _, key := range keys {
+		if n := len(report.Suites); n == 0 || report.Suites[n-1].Name != key.path {
+			report.Suites = append(report.Suites, junitTestSuite{Name: key.path})
+		}
+
+		suite := &report.Suites[len(report.Suites)-1]
+		tc := cases[key]
+		suite.Cases = append(suite.Cases, *tc)
+		suite.Tests++
+		report.Tests++
+		if len(tc.Failures) > 0 {
+			suite.Failures++
+			report.Failures++
+		}
+	}
+
+	return writeXML(w, report)
+}
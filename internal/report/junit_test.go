@@ -0,0 +1,56 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	file, err := codeowners.Parse(strings.NewReader(heredoc.Doc(`
+		# comment
+		*.md @writers
+		* @heaths
+	`)))
+	require.NoError(t, err)
+	file.Path = "CODEOWNERS"
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJUnit(&buf, file, testErrors))
+	assert.Equal(t, heredoc.Doc(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<testsuites name="gh-codeowners" tests="3" failures="2">
+		  <testsuite name="CODEOWNERS" tests="2" failures="1">
+		    <testcase name="line 2: *.md @writers" classname="CODEOWNERS" file="CODEOWNERS" line="2">
+		      <failure message="Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3" type="Shadowed rule"><![CDATA[Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3
+
+		  *.md @writers
+		  ^]]></failure>
+		    </testcase>
+		    <testcase name="line 3: * @heaths" classname="CODEOWNERS" file="CODEOWNERS" line="3"></testcase>
+		  </testsuite>
+		  <testsuite name="docs,old/CODEOWNERS" tests="1" failures="1">
+		    <testcase name="line 1: a|b @nobody" classname="docs,old/CODEOWNERS" file="docs,old/CODEOWNERS" line="1">
+		      <failure message="Unknown owner on line 1: make sure @nobody exists and has 100% write access" type="Unknown owner"><![CDATA[Unknown owner on line 1: make sure @nobody exists and has 100% write access]]></failure>
+		    </testcase>
+		  </testsuite>
+		</testsuites>
+	`), buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteJUnit(&buf, file, nil))
+	assert.Equal(t, heredoc.Doc(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<testsuites name="gh-codeowners" tests="2" failures="0">
+		  <testsuite name="CODEOWNERS" tests="2" failures="0">
+		    <testcase name="line 2: *.md @writers" classname="CODEOWNERS" file="CODEOWNERS" line="2"></testcase>
+		    <testcase name="line 3: * @heaths" classname="CODEOWNERS" file="CODEOWNERS" line="3"></testcase>
+		  </testsuite>
+		</testsuites>
+	`), buf.String())
+}
@@ -0,0 +1,205 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	toolName           = "gh-codeowners"
+	toolInformationURI = "https://github.com/heaths/gh-codeowners"
+)
+
+// knownKinds are always described as rules so rule indices are stable regardless of which errors were found.
+var knownKinds = []codeowners.ErrorKind{
+	codeowners.ErrorKindUnknownOwner,
+	codeowners.ErrorKindInvalidOwner,
+	codeowners.ErrorKindInvalidPattern,
+	codeowners.ErrorKindMissingOwners,
+	codeowners.ErrorKindNoMatchingFiles,
+	codeowners.ErrorKindShadowedRule,
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int           `json:"startLine"`
+	StartColumn int           `json:"startColumn,omitempty"`
+	Snippet     *sarifMessage `json:"snippet,omitempty"`
+}
+
+// WriteSARIF writes errors as a SARIF 2.1.0 log with a rule for each ErrorKind.
+// Rules are sorted by ID and results by path, line, and column so the output is deterministic.
+func WriteSARIF(w io.Writer, errors codeowners.Errors) error {
+	sorted := make(codeowners.Errors, len(errors))
+	copy(sorted, errors)
+	sorted.Sort()
+
+	kinds := make(map[codeowners.ErrorKind]bool)
+	for _, kind := range knownKinds {
+		kinds[kind] = true
+	}
+	for _, e := range sorted {
+		kinds[e.Kind] = true
+	}
+
+	rules := make([]sarifRule, 0, len(kinds))
+	for kind := range kinds {
+		rules = append(rules, sarifRule{
+			ID:                   RuleID(kind),
+			Name:                 ruleName(kind),
+			ShortDescription:     sarifMessage{Text: string(kind)},
+			DefaultConfiguration: sarifConfiguration{Level: Level(kind)},
+		})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	ruleIndex := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		ruleIndex[rule.ID] = i
+	}
+
+	results := make([]sarifResult, 0, len(sorted))
+	for _, e := range sorted {
+		id := RuleID(e.Kind)
+		region := sarifRegion{
+			StartLine:   e.Line,
+			StartColumn: e.Column,
+		}
+		if e.Source != "" {
+			region.Snippet = &sarifMessage{Text: e.Source}
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    id,
+			RuleIndex: ruleIndex[id],
+			Level:     Level(e.Kind),
+			Message:   sarifMessage{Text: e.Message},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{
+							URI:       e.Path,
+							URIBaseID: "%SRCROOT%",
+						},
+						Region: region,
+					},
+				},
+			},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           toolName,
+						InformationURI: toolInformationURI,
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// RuleID returns a stable identifier for kind e.g., "unknown-owner" for "Unknown owner".
+func RuleID(kind codeowners.ErrorKind) string {
+	return strings.Join(strings.Fields(strings.ToLower(string(kind))), "-")
+}
+
+// Level returns "warning" for rules reported only when requested, or "error" for errors reported by GitHub.
+func Level(kind codeowners.ErrorKind) string {
+	switch kind {
+	case codeowners.ErrorKindNoMatchingFiles, codeowners.ErrorKindShadowedRule:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// ruleName returns kind in PascalCase e.g., "UnknownOwner" for "Unknown owner".
+func ruleName(kind codeowners.ErrorKind) string {
+	var sb strings.Builder
+	for _, field := range strings.Fields(string(kind)) {
+		r := []rune(field)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+
+	return sb.String()
+}
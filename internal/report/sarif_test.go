@@ -0,0 +1,131 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/heaths/gh-codeowners/internal/codeowners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	errors := codeowners.Errors{
+		{
+			Kind:    codeowners.ErrorKindShadowedRule,
+			Path:    "CODEOWNERS",
+			Line:    2,
+			Column:  1,
+			Source:  "*.md @writers",
+			Message: "Shadowed rule on line 2: every file matched by *.md is also matched by line(s) 3\n\n  *.md @writers\n  ^",
+		},
+		{
+			Kind:    codeowners.ErrorKindUnknownOwner,
+			Path:    ".github/CODEOWNERS",
+			Line:    1,
+			Column:  3,
+			Source:  "* @nobody",
+			Message: "Unknown owner on line 1: make sure @nobody exists and has write access to the repository\n\n  * @nobody\n    ^",
+		},
+		{
+			Kind: "Unsupported syntax",
+			Path: ".github/CODEOWNERS",
+			Line: 4,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSARIF(&buf, errors))
+
+	// Output does not depend on the order of errors.
+	var reversed bytes.Buffer
+	require.NoError(t, WriteSARIF(&reversed, codeowners.Errors{errors[2], errors[1], errors[0]}))
+	assert.Equal(t, buf.String(), reversed.String())
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "gh-codeowners", run.Tool.Driver.Name)
+
+	var ids []string
+	for _, rule := range run.Tool.Driver.Rules {
+		ids = append(ids, rule.ID)
+	}
+	assert.Equal(t, []string{
+		"invalid-owner",
+		"invalid-pattern",
+		"missing-owners",
+		"no-matching-files",
+		"shadowed-rule",
+		"unknown-owner",
+		"unsupported-syntax",
+	}, ids)
+
+	assert.Equal(t, []sarifResult{
+		{
+			RuleID:    "unknown-owner",
+			RuleIndex: 5,
+			Level:     "error",
+			Message:   sarifMessage{Text: errors[1].Message},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: ".github/CODEOWNERS", URIBaseID: "%SRCROOT%"},
+						Region:           sarifRegion{StartLine: 1, StartColumn: 3, Snippet: &sarifMessage{Text: "* @nobody"}},
+					},
+				},
+			},
+		},
+		{
+			RuleID:    "unsupported-syntax",
+			RuleIndex: 6,
+			Level:     "error",
+			Message:   sarifMessage{},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: ".github/CODEOWNERS", URIBaseID: "%SRCROOT%"},
+						Region:           sarifRegion{StartLine: 4},
+					},
+				},
+			},
+		},
+		{
+			RuleID:    "shadowed-rule",
+			RuleIndex: 4,
+			Level:     "warning",
+			Message:   sarifMessage{Text: errors[0].Message},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "CODEOWNERS", URIBaseID: "%SRCROOT%"},
+						Region:           sarifRegion{StartLine: 2, StartColumn: 1, Snippet: &sarifMessage{Text: "*.md @writers"}},
+					},
+				},
+			},
+		},
+	}, run.Results)
+}
+
+func TestRuleID(t *testing.T) {
+	tests := []struct {
+		kind     codeowners.ErrorKind
+		wantID   string
+		wantName string
+	}{
+		{kind: codeowners.ErrorKindUnknownOwner, wantID: "unknown-owner", wantName: "UnknownOwner"},
+		{kind: codeowners.ErrorKindNoMatchingFiles, wantID: "no-matching-files", wantName: "NoMatchingFiles"},
+		{kind: "Single", wantID: "single", wantName: "Single"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			assert.Equal(t, tt.wantID, RuleID(tt.kind))
+			assert.Equal(t, tt.wantName, ruleName(tt.kind))
+		})
+	}
+}
@@ -9,8 +9,11 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
+	"github.com/cli/go-gh/pkg/repository"
 	"github.com/heaths/gh-codeowners/internal/cmd"
+	"github.com/heaths/gh-codeowners/internal/codeowners"
 	"github.com/heaths/go-console"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -19,6 +22,8 @@ import (
 const (
 	defaultColorComment = "#6A9955"
 	defaultColorError   = "#F44747"
+	defaultColorSection = "#569CD6"
+	defaultDialect      = codeowners.DialectGitHub
 )
 
 var (
@@ -53,44 +58,100 @@ func main() {
 		}
 	}
 
+	loadDialectConfig := func(key string, field *codeowners.Dialect) error {
+		val := v.GetString(key)
+		for _, dialect := range codeowners.Dialects() {
+			if strings.EqualFold(val, dialect) {
+				*field = codeowners.Dialect(dialect)
+				return nil
+			}
+		}
+
+		return &cmd.UsageError{Err: fmt.Errorf("config %q must be one of {%s}", key, strings.Join(codeowners.Dialects(), "|"))}
+	}
+
+	var (
+		exitZero bool
+		repo     string
+	)
 	rootCmd := cobra.Command{
 		Use:   "codeowners",
 		Short: "Check CODEOWNERS file",
 		Long:  "GitHub CLI extension to check your CODEOWNERS file.",
-		PersistentPreRun: func(_ *cobra.Command, _ []string) {
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
 			opts.Color = cmd.ColorOptions{
 				Comment: defaultColorComment,
 				Error:   defaultColorError,
+				Section: defaultColorSection,
 			}
+			opts.Dialect = defaultDialect
 
 			if err := v.ReadInConfig(); err != nil && opts.Verbose {
 				log.Printf("failed to load config: %q, skipping...", err)
-				return
 			}
 
 			loadColorConfig("color.comment", &opts.Color.Comment)
 			loadColorConfig("color.error", &opts.Color.Error)
+			loadColorConfig("color.section", &opts.Color.Section)
+
+			if repo != "" {
+				var err error
+				opts.Repo, err = repository.Parse(repo)
+				if err != nil {
+					return &cmd.UsageError{Err: fmt.Errorf("invalid repository %q: %w", repo, err)}
+				}
+				opts.Remote = true
+			}
+
+			return loadDialectConfig("dialect", &opts.Dialect)
 		},
-		SilenceUsage: true,
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 
 	// Output options
 	rootCmd.SetOut(con.Stdout())
 	rootCmd.PersistentFlags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Log verbose output.")
+	rootCmd.PersistentFlags().BoolVar(&exitZero, "exit-zero", false, "Exit with 0 even if errors are found in CODEOWNERS or coverage is too low.")
+
+	// Repository options
+	rootCmd.PersistentFlags().StringVarP(&repo, "repo", "R", "", "Select another repository using the [HOST/]OWNER/REPO format.")
 
 	// Colors options
 	rootCmd.PersistentFlags().String("color-comment", defaultColorComment, fmt.Sprintf("Hex RGB color code for comments e.g., %q.", defaultColorComment))
 	rootCmd.PersistentFlags().String("color-error", defaultColorError, fmt.Sprintf("Hex RGB color code for errors e.g., %q.", defaultColorError))
+	rootCmd.PersistentFlags().String("color-section", defaultColorSection, fmt.Sprintf("Hex RGB color code for GitLab section headers e.g., %q.", defaultColorSection))
 
 	_ = v.BindPFlag("color.comment", rootCmd.PersistentFlags().Lookup("color-comment"))
 	_ = v.BindPFlag("color.error", rootCmd.PersistentFlags().Lookup("color-error"))
+	_ = v.BindPFlag("color.section", rootCmd.PersistentFlags().Lookup("color-section"))
+
+	// Dialect options
+	rootCmd.PersistentFlags().String("dialect", string(defaultDialect), fmt.Sprintf("CODEOWNERS dialect: {%s}", strings.Join(codeowners.Dialects(), "|")))
+	_ = rootCmd.RegisterFlagCompletionFunc("dialect", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return codeowners.Dialects(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	_ = v.BindPFlag("dialect", rootCmd.PersistentFlags().Lookup("dialect"))
 
 	// Subcommands
+	rootCmd.AddCommand(cmd.CoverageCommand(opts))
+	rootCmd.AddCommand(cmd.DiffCommand(opts))
+	rootCmd.AddCommand(cmd.FilesCommand(opts))
 	rootCmd.AddCommand(cmd.LintCommand(opts))
+	rootCmd.AddCommand(cmd.LogCommand(opts))
 	rootCmd.AddCommand(cmd.PrCommand(opts))
+	rootCmd.AddCommand(cmd.StatsCommand(opts))
+	rootCmd.AddCommand(cmd.StatusCommand(opts))
 	rootCmd.AddCommand(cmd.ViewCommand(opts))
+	rootCmd.AddCommand(cmd.WhoCommand(opts))
+	cmd.SetUsageErrors(&rootCmd)
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	err := rootCmd.Execute()
+	code := cmd.ExitCode(err, exitZero)
+	if code != cmd.ExitOK {
+		fmt.Fprintf(con.Stderr(), "Error: %s\n", err)
 	}
+
+	os.Exit(code)
 }